@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"math/big"
@@ -127,17 +128,31 @@ func main() {
 		log.Panic(err)
 	}
 
-	webhook, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), service, metav1.GetOptions{})
-	if err != nil {
+	mutatingWebhook, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), service, metav1.GetOptions{})
+	if err == nil {
+		for i, wh := range mutatingWebhook.Webhooks {
+			wh.ClientConfig.CABundle = caPEM.Bytes()
+			mutatingWebhook.Webhooks[i] = wh
+		}
+
+		if _, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.Background(), mutatingWebhook, metav1.UpdateOptions{}); err != nil {
+			log.Panic(err)
+		}
+	} else if !k8serrors.IsNotFound(err) {
 		log.Panic(err)
 	}
 
-	for i, wh := range webhook.Webhooks {
-		wh.ClientConfig.CABundle = caPEM.Bytes()
-		webhook.Webhooks[i] = wh
-	}
-
-	if _, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.Background(), webhook, metav1.UpdateOptions{}); err != nil {
+	validatingWebhook, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), service, metav1.GetOptions{})
+	if err == nil {
+		for i, wh := range validatingWebhook.Webhooks {
+			wh.ClientConfig.CABundle = caPEM.Bytes()
+			validatingWebhook.Webhooks[i] = wh
+		}
+
+		if _, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.Background(), validatingWebhook, metav1.UpdateOptions{}); err != nil {
+			log.Panic(err)
+		}
+	} else if !k8serrors.IsNotFound(err) {
 		log.Panic(err)
 	}
 }
@@ -10,10 +10,8 @@ import (
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	topoapi "github.com/onosproject/onos-operator/pkg/apis/topo"
 	topoctrl "github.com/onosproject/onos-operator/pkg/controller/topo"
-	"github.com/onosproject/onos-operator/pkg/controller/topo/entity"
-	"github.com/onosproject/onos-operator/pkg/controller/topo/kind"
-	"github.com/onosproject/onos-operator/pkg/controller/topo/relation"
-	"github.com/onosproject/onos-operator/pkg/controller/topo/service"
+	"github.com/onosproject/onos-operator/pkg/controller/topo/defaults"
+	"github.com/onosproject/onos-operator/pkg/controller/topo/validation"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
 	"github.com/onosproject/onos-operator/pkg/controller/util/leader"
 	"github.com/onosproject/onos-operator/pkg/controller/util/ready"
@@ -83,20 +81,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Setup all Controllers
-	if err := entity.Add(mgr); err != nil {
+	// Add webhooks to the manager
+	if err := defaults.AddDefaulter(mgr); err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
-	if err := kind.Add(mgr); err != nil {
-		log.Error(err)
-		os.Exit(1)
-	}
-	if err := relation.Add(mgr); err != nil {
-		log.Error(err)
-		os.Exit(1)
-	}
-	if err := service.Add(mgr); err != nil {
+	if err := validation.AddValidator(mgr); err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
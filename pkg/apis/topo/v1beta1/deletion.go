@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// DeletionPolicy defines what happens to the topo object of a resource when the resource is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the topo object along with the resource
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the topo object in topo, still owned by the deleted resource
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan leaves the topo object in topo and releases its ownership so that it can be
+	// claimed by another resource
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
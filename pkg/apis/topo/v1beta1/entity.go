@@ -11,31 +11,31 @@ import (
 
 // EntitySpec is the k8s spec for a Entity resource
 type EntitySpec struct {
-	URI         string                          `json:"uri,omitempty"`
-	Kind        metav1.ObjectMeta               `json:"kind,omitempty"`
-	Aspects     map[string]runtime.RawExtension `json:"aspects,omitempty"`
-	ServiceName string                          `json:"serviceName,omitempty"`
+	URI     string                          `json:"uri,omitempty"`
+	Kind    metav1.ObjectMeta               `json:"kind,omitempty"`
+	Aspects map[string]runtime.RawExtension `json:"aspects,omitempty"`
+	// ObserveAspects are the types of the aspects of the topo object, e.g. written by other µONOS services, to report
+	// in the status of the Entity
+	ObserveAspects []string          `json:"observeAspects,omitempty"`
+	ServiceName    string            `json:"serviceName,omitempty"`
+	ServiceRef     *ServiceReference `json:"serviceRef,omitempty"`
+	DeletionPolicy DeletionPolicy    `json:"deletionPolicy,omitempty"`
 }
 
 // EntityState defines the states of an entity
-type EntityState string
-
-const (
-	// StatePending when entity is waiting for topo cluster
-	StatePending EntityState = "Pending"
-	// StateAdding when adding entity to topo
-	StateAdding EntityState = "Adding"
-	// StateAdded when entity is added to topo
-	StateAdded EntityState = "Added"
-	// StateRemoving when removing entity from topo
-	StateRemoving EntityState = "Removing"
-	// StateRemoved when entity is removed from topo OR topo service is not found (deleted)
-	StateRemoved EntityState = "Removed"
-)
+type EntityState = State
 
 // EntityStatus defines the observed state of Entity
 type EntityStatus struct {
-	State EntityState `json:"state"`
+	ObjectStatus `json:",inline"`
+	// AppliedURI is the URI of the topo object of the Entity, which is migrated when the URI in the spec changes
+	AppliedURI string `json:"appliedURI,omitempty"`
+	// AppliedReferences are the resource versions of the Secrets and ConfigMaps referenced by the aspects of the Entity
+	// when it was last applied, keyed by <kind>/<name>
+	AppliedReferences map[string]string `json:"appliedReferences,omitempty"`
+	// ObservedAspects are the values of the aspects of the topo object listed in the spec's observeAspects, as last
+	// observed in the topo store
+	ObservedAspects map[string]runtime.RawExtension `json:"observedAspects,omitempty"`
 }
 
 // +genclient
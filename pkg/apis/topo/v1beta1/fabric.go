@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// LinkPattern defines how the switches of a fabric are linked
+type LinkPattern string
+
+const (
+	// LinkPatternFullMesh links every leaf to every spine
+	LinkPatternFullMesh LinkPattern = "FullMesh"
+	// LinkPatternNone does not link the switches of the fabric
+	LinkPatternNone LinkPattern = "None"
+)
+
+// FabricKinds defines the names of the Kinds of the objects generated for a fabric
+type FabricKinds struct {
+	Switch     string `json:"switch,omitempty"`
+	Port       string `json:"port,omitempty"`
+	Link       string `json:"link,omitempty"`
+	Originates string `json:"originates,omitempty"`
+	Terminates string `json:"terminates,omitempty"`
+}
+
+// FabricAspects defines the aspect templates of the objects generated for a fabric
+type FabricAspects struct {
+	Spine map[string]runtime.RawExtension `json:"spine,omitempty"`
+	Leaf  map[string]runtime.RawExtension `json:"leaf,omitempty"`
+	Port  map[string]runtime.RawExtension `json:"port,omitempty"`
+	Link  map[string]runtime.RawExtension `json:"link,omitempty"`
+}
+
+// FabricTemplateSpec is the k8s spec for a FabricTemplate resource
+type FabricTemplateSpec struct {
+	Spines         int32             `json:"spines"`
+	Leaves         int32             `json:"leaves"`
+	PortsPerSwitch int32             `json:"portsPerSwitch"`
+	LinkPattern    LinkPattern       `json:"linkPattern,omitempty"`
+	Protocol       string            `json:"protocol,omitempty"`
+	Kinds          FabricKinds       `json:"kinds,omitempty"`
+	Aspects        FabricAspects     `json:"aspects,omitempty"`
+	ServiceName    string            `json:"serviceName,omitempty"`
+	ServiceRef     *ServiceReference `json:"serviceRef,omitempty"`
+	DeletionPolicy DeletionPolicy    `json:"deletionPolicy,omitempty"`
+}
+
+// FabricTemplateStatus defines the observed state of FabricTemplate
+type FabricTemplateStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// Switches is the number of switches in the fabric
+	Switches int32 `json:"switches"`
+	// Links is the number of links in the fabric
+	Links int32 `json:"links"`
+	// Members is the number of entities and relations generated for the fabric
+	Members int32 `json:"members"`
+	// Applied is the number of members that have been added to topo
+	Applied int32 `json:"applied"`
+	// Failed is the number of members that could not be added to topo
+	Failed int32 `json:"failed"`
+	// Progress summarizes the applied members of the fabric as "<applied>/<members>"
+	Progress string `json:"progress,omitempty"`
+	// Failures lists the members that could not be added to topo and the reasons for the failures
+	Failures []string `json:"failures,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FabricTemplate is the Schema for the FabricTemplate API
+// +k8s:openapi-gen=true
+type FabricTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              FabricTemplateSpec   `json:"spec,omitempty"`
+	Status            FabricTemplateStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FabricTemplateList contains a list of FabricTemplate
+type FabricTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FabricTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FabricTemplate{}, &FabricTemplateList{})
+}
@@ -5,17 +5,49 @@
 package v1beta1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DependentsPolicy defines how the deletion of a Kind affects the Entities and Relations of that kind
+type DependentsPolicy string
+
+const (
+	// DependentsPolicyBlock blocks the deletion of a Kind while Entities or Relations of that kind exist
+	DependentsPolicyBlock DependentsPolicy = "Block"
+	// DependentsPolicyCascade deletes the Relations and then the Entities of a Kind before deleting the Kind
+	DependentsPolicyCascade DependentsPolicy = "Cascade"
+)
+
+// AspectSchema declares an aspect of the Entities or Relations of a Kind
+type AspectSchema struct {
+	// Type is the aspect type, e.g. onos.topo.Switch
+	Type string `json:"type"`
+	// Required indicates whether the Entities or Relations of the Kind must define the aspect
+	Required bool `json:"required,omitempty"`
+	// Schema is an optional OpenAPI v3 schema the value of the aspect must conform to
+	Schema *apiextensionsv1.JSONSchemaProps `json:"schema,omitempty"`
+}
+
 // KindSpec is the k8s spec for a Kind resource
 type KindSpec struct {
-	Aspects map[string]runtime.RawExtension `json:"aspects,omitempty"`
+	Aspects          map[string]runtime.RawExtension `json:"aspects,omitempty"`
+	URITemplate      string                          `json:"uriTemplate,omitempty"`
+	EntityAspects    []AspectSchema                  `json:"entityAspects,omitempty"`
+	RelationAspects  []AspectSchema                  `json:"relationAspects,omitempty"`
+	ServiceName      string                          `json:"serviceName,omitempty"`
+	ServiceRef       *ServiceReference               `json:"serviceRef,omitempty"`
+	DependentsPolicy DependentsPolicy                `json:"dependentsPolicy,omitempty"`
+	DeletionPolicy   DeletionPolicy                  `json:"deletionPolicy,omitempty"`
 }
 
 // KindStatus defines the observed state of Kind
-type KindStatus struct{}
+type KindStatus struct {
+	ObjectStatus `json:",inline"`
+	// Dependents lists the Entities and Relations of the kind preventing its deletion
+	Dependents []string `json:"dependents,omitempty"`
+}
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
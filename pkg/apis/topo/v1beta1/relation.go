@@ -9,23 +9,35 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// RelationEndpoint represents the source or target or a Relation resource
+// RelationEndpoint represents the source or target or a Relation resource. The endpoint references an Entity
+// resource by name, a topo entity by URI, or both; the URI of a referenced Entity is used if no URI is set.
 type RelationEndpoint struct {
-	metav1.ObjectMeta `json:"metadata,omitempty"`
+	metav1.ObjectMeta `json:",inline"`
 	URI               string `json:"uri,omitempty"`
 }
 
 // RelationSpec is the k8s spec for a Relation resource
 type RelationSpec struct {
-	URI     string                          `json:"uri,omitempty"`
-	Kind    metav1.ObjectMeta               `json:"kind,omitempty"`
-	Source  RelationEndpoint                `json:"source,omitempty"`
-	Target  RelationEndpoint                `json:"target,omitempty"`
-	Aspects map[string]runtime.RawExtension `json:"aspects,omitempty"`
+	URI            string                          `json:"uri,omitempty"`
+	Kind           metav1.ObjectMeta               `json:"kind,omitempty"`
+	Source         RelationEndpoint                `json:"source,omitempty"`
+	Target         RelationEndpoint                `json:"target,omitempty"`
+	Aspects        map[string]runtime.RawExtension `json:"aspects,omitempty"`
+	ServiceName    string                          `json:"serviceName,omitempty"`
+	ServiceRef     *ServiceReference               `json:"serviceRef,omitempty"`
+	DeletionPolicy DeletionPolicy                  `json:"deletionPolicy,omitempty"`
 }
 
 // RelationStatus defines the observed state of Relation
-type RelationStatus struct{}
+type RelationStatus struct {
+	ObjectStatus `json:",inline"`
+	// AppliedURI is the URI of the topo object of the Relation, which is migrated when the URI in the spec changes
+	AppliedURI string `json:"appliedURI,omitempty"`
+	// SourceURI is the URI of the source entity applied to topo, resolved from the source Entity if referenced by name
+	SourceURI string `json:"sourceURI,omitempty"`
+	// TargetURI is the URI of the target entity applied to topo, resolved from the target Entity if referenced by name
+	TargetURI string `json:"targetURI,omitempty"`
+}
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -10,11 +10,45 @@ import (
 
 // ServiceSpec is the k8s spec for a Service resource
 type ServiceSpec struct {
-	Selector *metav1.LabelSelector `json:"selector"`
+	// Selector selects the pods of the onos-topo instance
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Address is the host:port address of the onos-topo instance, used when no Selector is set
+	Address string `json:"address,omitempty"`
+	// Port is the name of the onos-topo API port of the selected pods
+	Port string `json:"port,omitempty"`
+	// TLS configures the connection to the onos-topo instance
+	TLS *ServiceTLS `json:"tls,omitempty"`
+	// AllowedNamespaces lists the namespaces whose resources may use the Service from another namespace;
+	// "*" allows all namespaces
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// Mirror enables mirroring the objects of the onos-topo instance that are not managed by the operator
+	// into read-only Kind, Entity and Relation resources in the Service namespace
+	Mirror bool `json:"mirror,omitempty"`
+}
+
+// ServiceReference is a reference to a topo service in a possibly different namespace
+type ServiceReference struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ServiceTLS is the TLS configuration used to connect to an onos-topo instance
+type ServiceTLS struct {
+	// SecretName is the name of a kubernetes.io/tls Secret in the Service namespace, optionally
+	// including a ca.crt used to verify the onos-topo server certificate instead of the system roots
+	SecretName string `json:"secretName,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables the verification of the onos-topo server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 // ServiceStatus defines the observed state of Service
-type ServiceStatus struct{}
+type ServiceStatus struct {
+	Endpoints      []string           `json:"endpoints,omitempty"`
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+	ManagedObjects int32              `json:"managedObjects"`
+	LastProbeTime  *metav1.Time       `json:"lastProbeTime,omitempty"`
+}
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
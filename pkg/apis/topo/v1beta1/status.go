@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// State defines the states of a topology resource
+type State string
+
+const (
+	// StatePending when the resource is waiting for topo cluster
+	StatePending State = "Pending"
+	// StateAdding when adding the resource to topo
+	StateAdding State = "Adding"
+	// StateAdded when the resource is added to topo
+	StateAdded State = "Added"
+	// StateRemoving when removing the resource from topo
+	StateRemoving State = "Removing"
+	// StateRemoved when the resource is removed from topo OR topo service is not found (deleted)
+	StateRemoved State = "Removed"
+)
+
+const (
+	// ConditionReady indicates whether the latest spec of the resource has been added to topo
+	ConditionReady = "Ready"
+	// ConditionSynced indicates whether the last synchronization of the resource with topo succeeded
+	ConditionSynced = "Synced"
+	// ConditionDependenciesResolved indicates whether the objects the resource depends on are available
+	ConditionDependenciesResolved = "DependenciesResolved"
+	// ConditionDeletionBlocked indicates whether the deletion of the resource is waiting for its dependents
+	ConditionDeletionBlocked = "DeletionBlocked"
+	// ConditionConnected indicates whether the onos-topo instance of a Service is reachable
+	ConditionConnected = "Connected"
+)
+
+const (
+	// ReasonServiceNotFound when the topo service for the resource is not found
+	ReasonServiceNotFound = "ServiceNotFound"
+	// ReasonServiceNotAllowed when the topo service for the resource does not allow the resource's namespace
+	ReasonServiceNotAllowed = "ServiceNotAllowed"
+	// ReasonSynced when the resource has been synchronized with topo
+	ReasonSynced = "Synced"
+	// ReasonSyncFailed when the resource could not be synchronized with topo
+	ReasonSyncFailed = "SyncFailed"
+	// ReasonOwnershipConflict when the topo object of the resource is owned by another resource
+	ReasonOwnershipConflict = "OwnershipConflict"
+	// ReasonInvalidAspects when the aspects of the resource do not conform to the aspect schemas of its Kind
+	ReasonInvalidAspects = "InvalidAspects"
+	// ReasonResolved when the dependencies of the resource have been resolved
+	ReasonResolved = "Resolved"
+	// ReasonWaitingForDependencies when the objects the resource depends on have not been added to topo
+	ReasonWaitingForDependencies = "WaitingForDependencies"
+	// ReasonUnresolvedReference when an Entity referenced by name by the resource does not exist
+	ReasonUnresolvedReference = "UnresolvedReference"
+	// ReasonDependentsExist when the deletion of the resource is blocked by its dependents
+	ReasonDependentsExist = "DependentsExist"
+	// ReasonCascadingDeletion when the dependents of the resource are being deleted
+	ReasonCascadingDeletion = "CascadingDeletion"
+	// ReasonApplying when the members of a Topology are being added to topo
+	ReasonApplying = "Applying"
+	// ReasonApplied when all the members of a Topology have been added to topo
+	ReasonApplied = "Applied"
+	// ReasonApplyFailed when members of a Topology could not be added to topo
+	ReasonApplyFailed = "ApplyFailed"
+	// ReasonInvalidTemplate when a FabricTemplate cannot be expanded into topology resources
+	ReasonInvalidTemplate = "InvalidTemplate"
+	// ReasonConnected when the onos-topo instance of a Service is reachable
+	ReasonConnected = "Connected"
+	// ReasonNoEndpoints when no endpoints are available for a Service
+	ReasonNoEndpoints = "NoEndpoints"
+	// ReasonConnectionFailed when the onos-topo instance of a Service could not be reached
+	ReasonConnectionFailed = "ConnectionFailed"
+	// ReasonTargetNotAllowed when the address of a Service points outside the Service's namespace
+	ReasonTargetNotAllowed = "TargetNotAllowed"
+)
+
+// DriftType defines the types of differences between a resource and its topo object
+type DriftType string
+
+const (
+	// DriftMissing when the object was not found in topo
+	DriftMissing DriftType = "Missing"
+	// DriftModified when the object in topo differs from the resource spec
+	DriftModified DriftType = "Modified"
+)
+
+// Drift records a difference found between a resource and its topo object
+type Drift struct {
+	Type         DriftType   `json:"type"`
+	Message      string      `json:"message,omitempty"`
+	DetectedTime metav1.Time `json:"detectedTime"`
+}
+
+// ObjectStatus defines the observed state common to all topology resources
+type ObjectStatus struct {
+	State              State              `json:"state,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	AppliedAspects     []string           `json:"appliedAspects,omitempty"`
+	AppliedLabels      map[string]string  `json:"appliedLabels,omitempty"`
+	LastSyncTime       *metav1.Time       `json:"lastSyncTime,omitempty"`
+	LastError          string             `json:"lastError,omitempty"`
+	LastDrift          *Drift             `json:"lastDrift,omitempty"`
+	// Claimed indicates the topo object of the resource was added by a previous version of the operator that did
+	// not record ownership, so the resource may claim the object
+	Claimed bool `json:"claimed,omitempty"`
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KindTemplate defines a Kind member of a Topology
+type KindTemplate struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Spec   KindSpec          `json:"spec,omitempty"`
+}
+
+// EntityTemplate defines an Entity member of a Topology
+type EntityTemplate struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Spec   EntitySpec        `json:"spec,omitempty"`
+}
+
+// RelationTemplate defines a Relation member of a Topology
+type RelationTemplate struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Spec   RelationSpec      `json:"spec,omitempty"`
+}
+
+// TopologySpec is the k8s spec for a Topology resource
+type TopologySpec struct {
+	Kinds     []KindTemplate     `json:"kinds,omitempty"`
+	Entities  []EntityTemplate   `json:"entities,omitempty"`
+	Relations []RelationTemplate `json:"relations,omitempty"`
+}
+
+// TopologyStatus defines the observed state of Topology
+type TopologyStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// Members is the number of kinds, entities and relations in the topology
+	Members int32 `json:"members"`
+	// Applied is the number of members that have been added to topo
+	Applied int32 `json:"applied"`
+	// Failed is the number of members that could not be added to topo
+	Failed int32 `json:"failed"`
+	// Progress summarizes the applied members of the topology as "<applied>/<members>"
+	Progress string `json:"progress,omitempty"`
+	// Failures lists the members that could not be added to topo and the reasons for the failures
+	Failures []string `json:"failures,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Topology is the Schema for the Topology API
+// +k8s:openapi-gen=true
+type Topology struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TopologySpec   `json:"spec,omitempty"`
+	Status            TopologyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TopologyList contains a list of Topology
+type TopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Topology `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Topology{}, &TopologyList{})
+}
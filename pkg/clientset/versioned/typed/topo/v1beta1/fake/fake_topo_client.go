@@ -32,6 +32,10 @@ func (c *FakeTopoV1beta1) Entities(namespace string) v1beta1.EntityInterface {
 	return &FakeEntities{c, namespace}
 }
 
+func (c *FakeTopoV1beta1) FabricTemplates(namespace string) v1beta1.FabricTemplateInterface {
+	return &FakeFabricTemplates{c, namespace}
+}
+
 func (c *FakeTopoV1beta1) Kinds(namespace string) v1beta1.KindInterface {
 	return &FakeKinds{c, namespace}
 }
@@ -44,6 +48,10 @@ func (c *FakeTopoV1beta1) Services(namespace string) v1beta1.ServiceInterface {
 	return &FakeServices{c, namespace}
 }
 
+func (c *FakeTopoV1beta1) Topologies(namespace string) v1beta1.TopologyInterface {
+	return &FakeTopologies{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeTopoV1beta1) RESTClient() rest.Interface {
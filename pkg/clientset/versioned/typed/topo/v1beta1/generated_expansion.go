@@ -20,8 +20,12 @@ package v1beta1
 
 type EntityExpansion interface{}
 
+type FabricTemplateExpansion interface{}
+
 type KindExpansion interface{}
 
 type RelationExpansion interface{}
 
 type ServiceExpansion interface{}
+
+type TopologyExpansion interface{}
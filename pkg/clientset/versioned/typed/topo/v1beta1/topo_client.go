@@ -27,9 +27,11 @@ import (
 type TopoV1beta1Interface interface {
 	RESTClient() rest.Interface
 	EntitiesGetter
+	FabricTemplatesGetter
 	KindsGetter
 	RelationsGetter
 	ServicesGetter
+	TopologiesGetter
 }
 
 // TopoV1beta1Client is used to interact with features provided by the topo.onosproject.org group.
@@ -41,6 +43,10 @@ func (c *TopoV1beta1Client) Entities(namespace string) EntityInterface {
 	return newEntities(c, namespace)
 }
 
+func (c *TopoV1beta1Client) FabricTemplates(namespace string) FabricTemplateInterface {
+	return newFabricTemplates(c, namespace)
+}
+
 func (c *TopoV1beta1Client) Kinds(namespace string) KindInterface {
 	return newKinds(c, namespace)
 }
@@ -53,6 +59,10 @@ func (c *TopoV1beta1Client) Services(namespace string) ServiceInterface {
 	return newServices(c, namespace)
 }
 
+func (c *TopoV1beta1Client) Topologies(namespace string) TopologyInterface {
+	return newTopologies(c, namespace)
+}
+
 // NewForConfig creates a new TopoV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*TopoV1beta1Client, error) {
 	config := *c
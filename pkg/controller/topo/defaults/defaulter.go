@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package defaults
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/uris"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var log = logging.GetLogger("controller", "topo", "defaults")
+
+const defaultPath = "/mutate-topo"
+
+// AddDefaulter adds the topology defaulting webhook to the manager
+func AddDefaulter(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(defaultPath, &webhook.Admission{
+		Handler: &Defaulter{
+			client: mgr.GetAPIReader(),
+			scheme: mgr.GetScheme(),
+		},
+	})
+	return nil
+}
+
+// Defaulter is a mutating webhook that defaults the URIs of topology resources from the URI templates of their Kinds
+type Defaulter struct {
+	// client reads Kinds and Entities directly from the API server, since a Kind or Entity may have been
+	// created just before the resources referencing it
+	client  client.Reader
+	scheme  *runtime.Scheme
+	decoder *admission.Decoder
+}
+
+// InjectDecoder :
+func (d *Defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle :
+func (d *Defaulter) Handle(ctx context.Context, request admission.Request) admission.Response {
+	namespacedName := types.NamespacedName{
+		Namespace: request.Namespace,
+		Name:      request.Name,
+	}
+	log.Infof("Received admission request for %s '%s'", request.Kind.Kind, namespacedName)
+
+	var object, oldObject client.Object
+	switch request.Kind.Kind {
+	case "Entity":
+		object, oldObject = &v1beta1.Entity{}, &v1beta1.Entity{}
+	case "Relation":
+		object, oldObject = &v1beta1.Relation{}, &v1beta1.Relation{}
+	case "Topology":
+		object, oldObject = &v1beta1.Topology{}, &v1beta1.Topology{}
+	default:
+		return admission.Allowed("")
+	}
+
+	if err := d.decoder.Decode(request, object); err != nil {
+		log.Errorf("Could not decode %s '%s'", request.Kind.Kind, namespacedName, err)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if request.Operation == admissionv1.Update {
+		if err := d.decoder.DecodeRaw(request.OldObject, oldObject); err != nil {
+			log.Errorf("Could not decode %s '%s'", request.Kind.Kind, namespacedName, err)
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	} else {
+		oldObject = nil
+	}
+
+	// Mirrored resources reflect the objects in topo as they are
+	if mirrors.IsMirrored(object) || object.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+
+	errs, err := d.setDefaults(ctx, request.Namespace, object, oldObject)
+	if err != nil {
+		log.Errorf("Could not default %s '%s'", request.Kind.Kind, namespacedName, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(errs) > 0 {
+		log.Infof("Rejecting %s '%s': %s", request.Kind.Kind, namespacedName, errs.ToAggregate())
+		gk := schema.GroupKind{Group: request.Kind.Group, Kind: request.Kind.Kind}
+		status := k8serrors.NewInvalid(gk, request.Name, errs).ErrStatus
+		return admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &status,
+			},
+		}
+	}
+
+	// Marshal the resource and return a patch response
+	marshaledObject, err := json.Marshal(object)
+	if err != nil {
+		log.Errorf("Could not default %s '%s'", request.Kind.Kind, namespacedName, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(request.Object.Raw, marshaledObject)
+}
+
+// kindGetter returns the spec of the Kind referenced by a resource, or nil if the Kind does not exist
+type kindGetter func(ref metav1.ObjectMeta) (*v1beta1.KindSpec, error)
+
+// endpointResolver returns the URI of the entity at an endpoint of a relation, or an empty string if the endpoint
+// cannot be resolved
+type endpointResolver func(endpoint v1beta1.RelationEndpoint) (string, error)
+
+// setDefaults sets the defaults of the given resource, returning an error for each default that could not be set
+func (d *Defaulter) setDefaults(ctx context.Context, namespace string, object, oldObject client.Object) (field.ErrorList, error) {
+	getKind := func(ref metav1.ObjectMeta) (*v1beta1.KindSpec, error) {
+		kind, err := dependencies.GetKind(ctx, d.client, namespace, ref)
+		if err != nil || kind == nil {
+			return nil, err
+		}
+		return &kind.Spec, nil
+	}
+	resolveEndpoint := func(endpoint v1beta1.RelationEndpoint) (string, error) {
+		return dependencies.ResolveEndpoint(ctx, d.client, namespace, endpoint)
+	}
+
+	specPath := field.NewPath("spec")
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		// Keep the URI of an existing entity rather than rendering it again from a changed resource
+		if old, ok := oldObject.(*v1beta1.Entity); ok && o.Spec.URI == "" {
+			o.Spec.URI = old.Spec.URI
+		}
+		return setEntityURI(specPath, getKind, namespace, o.ObjectMeta, &o.Spec)
+	case *v1beta1.Relation:
+		if old, ok := oldObject.(*v1beta1.Relation); ok && o.Spec.URI == "" {
+			o.Spec.URI = old.Spec.URI
+		}
+		return setRelationURI(specPath, getKind, resolveEndpoint, namespace, o.ObjectMeta, &o.Spec)
+	case *v1beta1.Topology:
+		// Resolve the Kinds of the members from the Kinds of the Topology before the Kinds in the cluster
+		kinds := make(map[types.NamespacedName]*v1beta1.KindSpec)
+		for i, template := range o.Spec.Kinds {
+			kinds[types.NamespacedName{Namespace: namespace, Name: template.Name}] = &o.Spec.Kinds[i].Spec
+		}
+		getMemberKind := func(ref metav1.ObjectMeta) (*v1beta1.KindSpec, error) {
+			if spec, ok := kinds[dependencies.GetNamespacedName(namespace, ref)]; ok {
+				return spec, nil
+			}
+			return getKind(ref)
+		}
+
+		var errs field.ErrorList
+		for i := range o.Spec.Entities {
+			template := &o.Spec.Entities[i]
+			meta := metav1.ObjectMeta{Name: template.Name, Labels: template.Labels}
+			templateErrs, err := setEntityURI(specPath.Child("entities").Index(i).Child("spec"), getMemberKind, namespace, meta, &template.Spec)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, templateErrs...)
+		}
+
+		// Resolve the endpoints of the member relations from the entities of the Topology before the
+		// Entities in the cluster
+		entities := make(map[types.NamespacedName]string)
+		for _, template := range o.Spec.Entities {
+			entities[types.NamespacedName{Namespace: namespace, Name: template.Name}] = template.Spec.URI
+		}
+		resolveMemberEndpoint := func(endpoint v1beta1.RelationEndpoint) (string, error) {
+			if uri, ok := entities[dependencies.GetNamespacedName(namespace, endpoint.ObjectMeta)]; ok && endpoint.URI == "" {
+				return uri, nil
+			}
+			return resolveEndpoint(endpoint)
+		}
+		for i := range o.Spec.Relations {
+			template := &o.Spec.Relations[i]
+			meta := metav1.ObjectMeta{Name: template.Name, Labels: template.Labels}
+			templateErrs, err := setRelationURI(specPath.Child("relations").Index(i).Child("spec"), getMemberKind, resolveMemberEndpoint, namespace, meta, &template.Spec)
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, templateErrs...)
+		}
+		return errs, nil
+	}
+	return nil, nil
+}
+
+// setEntityURI renders the URI of the given entity from the URI template of its kind if the URI is not set
+func setEntityURI(path *field.Path, getKind kindGetter, namespace string, meta metav1.ObjectMeta, spec *v1beta1.EntitySpec) (field.ErrorList, error) {
+	if spec.URI != "" {
+		return nil, nil
+	}
+	kind, err := getKind(spec.Kind)
+	if err != nil || kind == nil || kind.URITemplate == "" {
+		return nil, err
+	}
+	data, err := uris.NewData(meta.Name, namespace, meta.Labels, meta.Annotations, spec.Kind.Name, spec.Aspects)
+	if err == nil {
+		spec.URI, err = uris.Render(kind.URITemplate, data)
+	}
+	if err != nil {
+		return field.ErrorList{field.Invalid(path.Child("uri"), spec.URI, fmt.Sprintf("failed to render the URI template of kind %s: %s", spec.Kind.Name, err))}, nil
+	}
+	return nil, nil
+}
+
+// setRelationURI renders the URI of the given relation from the URI template of its kind if the URI is not set,
+// or derives it from the kind, source and target of the relation if the kind does not define a URI template
+func setRelationURI(path *field.Path, getKind kindGetter, resolveEndpoint endpointResolver, namespace string, meta metav1.ObjectMeta, spec *v1beta1.RelationSpec) (field.ErrorList, error) {
+	if spec.URI != "" {
+		return nil, nil
+	}
+	kind, err := getKind(spec.Kind)
+	if err != nil {
+		return nil, err
+	}
+	source, err := resolveEndpoint(spec.Source)
+	if err != nil {
+		return nil, err
+	}
+	target, err := resolveEndpoint(spec.Target)
+	if err != nil {
+		return nil, err
+	}
+	if kind == nil || kind.URITemplate == "" {
+		if spec.Kind.Name != "" && source != "" && target != "" {
+			spec.URI = uris.GetRelationURI(spec.Kind.Name, source, target)
+		}
+		return nil, nil
+	}
+	data, err := uris.NewData(meta.Name, namespace, meta.Labels, meta.Annotations, spec.Kind.Name, spec.Aspects)
+	if err == nil {
+		data.Source = source
+		data.Target = target
+		spec.URI, err = uris.Render(kind.URITemplate, data)
+	}
+	if err != nil {
+		return field.ErrorList{field.Invalid(path.Child("uri"), spec.URI, fmt.Sprintf("failed to render the URI template of kind %s: %s", spec.Kind.Name, err))}, nil
+	}
+	return nil, nil
+}
+
+var _ admission.Handler = &Defaulter{}
@@ -6,25 +6,41 @@ package entity
 
 import (
 	"context"
+	"fmt"
 	prototypes "github.com/gogo/protobuf/types"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
 	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/labels"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
+	"time"
 )
 
 var log = logging.GetLogger("controller", "topo", "entity")
@@ -33,11 +49,15 @@ const topoFinalizer = "topo.onosproject.org/entity"
 
 // Add creates a new Entity controller and adds it to the Manager. The Manager will set fields on the
 // controller and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+func Add(mgr manager.Manager, conns *grpc.ConnManager, watcher *watchers.Watcher) error {
 	r := &Reconciler{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		config: mgr.GetConfig(),
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		conns:          conns,
+		watcher:        watcher,
+		resyncInterval: k8s.GetResyncInterval(),
+		labelMapping:   labels.GetMapping(),
 	}
 
 	// Create a new controller
@@ -52,15 +72,22 @@ func Add(mgr manager.Manager) error {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+	// Watch for changes to the topo objects of Entity resources made in the topo store
+	err = c.Watch(&source.Channel{Source: watcher.Entities()}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the state and aspect schemas of Kinds and requeue the entity resources that depend on them
+	err = c.Watch(&source.Kind{Type: &v1beta1.Kind{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
 		topoEntityList := &v1beta1.EntityList{}
-		if err := mgr.GetClient().List(context.Background(), topoEntityList, &client.ListOptions{Namespace: object.GetNamespace()}); err != nil {
+		if err := mgr.GetClient().List(context.Background(), topoEntityList, client.MatchingFields{dependencies.KindNameField: object.GetName()}); err != nil {
 			log.Error(err)
 			return nil
 		}
 		var requests []reconcile.Request
 		for _, entity := range topoEntityList.Items {
-			if object.GetName() == entity.Spec.ServiceName {
+			if dependencies.GetNamespacedName(entity.Namespace, entity.Spec.Kind) == client.ObjectKeyFromObject(object) {
 				requests = append(requests, reconcile.Request{
 					NamespacedName: types.NamespacedName{
 						Namespace: entity.Namespace,
@@ -70,7 +97,75 @@ func Add(mgr manager.Manager) error {
 			}
 		}
 		return requests
-	}))
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return dependencies.StateChanged(e.ObjectOld, e.ObjectNew) || e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Secrets and ConfigMaps and requeue the entity resources with aspects referencing them
+	referenceHandler := func(kind string) handler.EventHandler {
+		return handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			topoEntityList := &v1beta1.EntityList{}
+			if err := mgr.GetClient().List(context.Background(), topoEntityList, client.InNamespace(object.GetNamespace()),
+				client.MatchingFields{aspects.ReferencesField: aspects.GetReferenceKey(kind, object.GetName())}); err != nil {
+				log.Error(err)
+				return nil
+			}
+			var requests []reconcile.Request
+			for _, entity := range topoEntityList.Items {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: entity.Namespace,
+						Name:      entity.Name,
+					},
+				})
+			}
+			return requests
+		})
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, referenceHandler("Secret"))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, referenceHandler("ConfigMap"))
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to topo services and requeue the entity resources that use them
+	serviceHandler := handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		keys, err := services.GetIndexKeys(context.Background(), mgr.GetClient(), client.ObjectKeyFromObject(object))
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, key := range keys {
+			entityList := &v1beta1.EntityList{}
+			if err := mgr.GetClient().List(context.Background(), entityList, client.MatchingFields{services.ServiceField: key}); err != nil {
+				log.Error(err)
+				return nil
+			}
+			for _, entity := range entityList.Items {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: entity.Namespace,
+						Name:      entity.Name,
+					},
+				})
+			}
+		}
+		return requests
+	})
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, serviceHandler)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, serviceHandler)
 	if err != nil {
 		return err
 	}
@@ -84,6 +179,13 @@ type Reconciler struct {
 	client client.Client
 	scheme *runtime.Scheme
 	config *rest.Config
+	conns  *grpc.ConnManager
+	// watcher watches topo services for changes to the topo objects of the resources
+	watcher *watchers.Watcher
+	// resyncInterval is the interval at which added entities are verified against the topo store
+	resyncInterval time.Duration
+	// labelMapping selects the labels and annotations of entities that are copied to the labels of their topo objects
+	labelMapping labels.Mapping
 }
 
 // Reconcile reads that state of the cluster for a Entity object and makes changes based on the state read
@@ -97,7 +199,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Forget the changes watched for the topo object of the deleted entity.
 			// Return and don't requeue
+			r.watcher.ClearChanged(&v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}})
 			return reconcile.Result{}, nil
 		}
 		log.Warnf("Failed to reconcile entity %s in namespace %s, %s", request.Name, request.Namespace, err)
@@ -105,6 +209,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, err
 	}
 
+	// Mirrored entity resources are managed by the mirror controller
+	if mirrors.IsMirrored(entity) {
+		return reconcile.Result{}, nil
+	}
+
+	// Claim the topo objects added by a previous version of the operator, which did not record ownership
+	if owners.ClaimLegacy(entity, topoFinalizer, &entity.Status.ObjectStatus) {
+		log.Infof("Claiming topo object of entity %s, %s added by a previous version of the operator", entity.Name, entity.Namespace)
+		if err := r.client.Status().Update(ctx, entity); err != nil {
+			log.Warnf("Failed to reconcile claiming entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	if entity.DeletionTimestamp == nil {
 		return r.reconcileCreate(ctx, entity)
 	}
@@ -112,26 +231,51 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcileCreate(ctx context.Context, entity *v1beta1.Entity) (reconcile.Result, error) {
-	// Get topo service name
-	topoServiceName := entity.Spec.ServiceName
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, entity.Namespace, entity.Spec.ServiceRef, entity.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
 
 	// Check if topo service is available
-	topoNamespacedName := types.NamespacedName{Namespace: entity.Namespace, Name: topoServiceName}
-	topoServiceObject := &corev1.Service{}
-	if err := r.client.Get(ctx, topoNamespacedName, topoServiceObject); err != nil && k8serrors.IsNotFound(err) {
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
 		// Set the state to StatePending if topo service is not found (deleted).
-		entity.Status = v1beta1.EntityStatus{State: v1beta1.StatePending}
+		conditions.SetPending(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonServiceNotFound,
+			fmt.Sprintf("topo service %s not found", topoService))
 		if err := r.client.Status().Update(ctx, entity); err != nil {
 			log.Warnf("Failed to update state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
 			return reconcile.Result{}, err
 		}
-		log.Warnf("Failed to find topo service %s in namespace %s, %s", topoServiceName, entity.Namespace, err)
+		log.Warnf("Failed to find topo service %s in namespace %s, %s", topoService.Name, topoService.Namespace, err)
 		return reconcile.Result{}, err
 	}
 
+	// Check if the topo service may be used from the entity's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, entity.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		conditions.SetPending(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonServiceNotAllowed,
+			fmt.Sprintf("topo service %s does not allow namespace %s", topoService, entity.Namespace))
+		if err := r.client.Status().Update(ctx, entity); err != nil {
+			log.Warnf("Failed to update state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		log.Warnf("Topo service %s does not allow namespace %s", topoService, entity.Namespace)
+		return reconcile.Result{}, nil
+	}
+
+	// Watch the topo service for changes to the topo object of the entity
+	r.watcher.Watch(topoService)
+
 	switch entity.Status.State {
 	case v1beta1.StatePending:
-		entity.Status = v1beta1.EntityStatus{State: v1beta1.StateAdding}
+		conditions.SetState(&entity.Status.ObjectStatus, v1beta1.StateAdding, entity.Generation)
 		err := r.client.Status().Update(ctx, entity)
 		if err != nil {
 			log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
@@ -149,28 +293,64 @@ func (r *Reconciler) reconcileCreate(ctx context.Context, entity *v1beta1.Entity
 			}
 			return reconcile.Result{}, nil
 		}
+		// Wait for the objects the entity depends on to be added to topo
+		if message, err := dependencies.CheckKind(ctx, r.client, entity.Namespace, entity.Spec.Kind); err != nil {
+			log.Warnf("Failed to reconcile dependencies of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		} else if message != "" {
+			return r.waitForDependencies(ctx, entity, v1beta1.ReasonWaitingForDependencies, message)
+		}
+		// Resolve the aspect values referenced in Secrets and ConfigMaps
+		resolved, err := aspects.Resolve(ctx, r.client, entity.Namespace, entity.Spec.Aspects)
+		if err != nil {
+			log.Warnf("Failed to reconcile aspects of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		} else if len(resolved.Unresolved) > 0 {
+			return r.waitForDependencies(ctx, entity, v1beta1.ReasonUnresolvedReference, strings.Join(resolved.Unresolved, "; "))
+		}
+		// Check the aspects of the entity against the aspect schemas declared by its kind
+		if errs, err := r.validateAspects(ctx, entity, resolved); err != nil {
+			log.Warnf("Failed to reconcile aspects of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		} else if len(errs) > 0 {
+			return r.invalidAspects(ctx, entity, errs.ToAggregate())
+		}
 		// Connect to the topology service
-		conn, err := grpc.ConnectService(r.client, entity.Namespace, topoServiceName)
+		conn, err := r.conns.Connect(ctx, topoService)
 		if err != nil {
 			log.Warnf("Failed to reconcile creating entity %s, %s, %s", entity.Name, entity.Namespace, err)
-			return reconcile.Result{}, err
+			return r.syncFailed(ctx, entity, err)
+		}
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Delete the topo object of the previously applied URI if the URI of the entity has changed
+		if err := r.migrateEntity(ctx, entity, topoService, client); err != nil {
+			log.Warnf("Failed to reconcile migrating entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return r.syncFailed(ctx, entity, err)
 		}
-		defer conn.Close()
-		client := topo.NewTopoClient(conn)
 		// Check if the entity exists in the topology and return it for update if so
-		if object, err := r.entityExists(ctx, entity, client); err != nil {
-			return reconcile.Result{}, err
+		if object, err := r.entityExists(ctx, entity.Spec.URI, client); err != nil {
+			return r.syncFailed(ctx, entity, err)
 		} else if object != nil {
-			if err := r.updateEntity(ctx, entity, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			if err := owners.Check(object, entity, owners.CanClaim(&entity.Status.ObjectStatus, object, getAppliedURI(entity))); err != nil {
+				return r.ownershipConflict(ctx, entity, err)
+			}
+			if err := r.updateEntity(ctx, entity, resolved.Aspects, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
 				log.Warnf("Failed to reconcile creating entity %s, %s, %s", entity.Name, entity.Namespace, err)
-				return reconcile.Result{}, err
+				return r.syncFailed(ctx, entity, err)
 			}
 		}
-		if err := r.createEntity(ctx, entity, client); err != nil && !errors.IsAlreadyExists(err) {
+		if err := r.createEntity(ctx, entity, resolved.Aspects, client); err != nil && !errors.IsAlreadyExists(err) {
 			log.Warnf("Failed to reconcile creating entity %s, %s, %s", entity.Name, entity.Namespace, err)
-			return reconcile.Result{}, err
+			return r.syncFailed(ctx, entity, err)
 		}
-		entity.Status = v1beta1.EntityStatus{State: v1beta1.StateAdded}
+		entity.Status.AppliedAspects = aspects.Types(resolved.Aspects)
+		entity.Status.AppliedLabels = r.labelMapping.Get(entity)
+		entity.Status.AppliedURI = entity.Spec.URI
+		entity.Status.AppliedReferences = resolved.Versions
+		conditions.SetState(&entity.Status.ObjectStatus, v1beta1.StateAdded, entity.Generation)
+		conditions.SetDependenciesResolved(&entity.Status.ObjectStatus, entity.Generation, true, v1beta1.ReasonResolved, "")
+		conditions.SetSynced(&entity.Status.ObjectStatus, entity.Generation)
 		err = r.client.Status().Update(ctx, entity)
 		if err != nil {
 			log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
@@ -178,38 +358,191 @@ func (r *Reconciler) reconcileCreate(ctx context.Context, entity *v1beta1.Entity
 		}
 		return reconcile.Result{}, nil
 	case v1beta1.StateAdded:
-		log.Debugf("Entity %s is already added to topo store.", entity.Name)
-		return reconcile.Result{}, nil
+		return r.reconcileAdded(ctx, entity, topoService)
 	}
 	return reconcile.Result{}, nil
 }
 
+// reconcileAdded propagates spec changes of an added entity to the topo store and periodically verifies it
+// against the topo store, re-creating or repairing the topo object if it has drifted from the Entity spec
+func (r *Reconciler) reconcileAdded(ctx context.Context, entity *v1beta1.Entity, topoService types.NamespacedName) (reconcile.Result, error) {
+	// Resolve the aspect values referenced in Secrets and ConfigMaps, which change when the referenced values change
+	resolved, err := aspects.Resolve(ctx, r.client, entity.Namespace, entity.Spec.Aspects)
+	if err != nil {
+		log.Warnf("Failed to reconcile aspects of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// If the spec, mapped labels and referenced values have not changed since they were last applied and the topo
+	// object has not been changed in the topo store, wait for the resync interval to elapse
+	modified := entity.Status.ObservedGeneration != entity.Generation ||
+		!aspects.EqualVersions(resolved.Versions, entity.Status.AppliedReferences) ||
+		!labels.Equal(r.labelMapping.Get(entity), entity.Status.AppliedLabels)
+	changed := r.watcher.ClearChanged(entity)
+	if !modified && !changed {
+		if r.resyncInterval == 0 {
+			log.Debugf("Entity %s is already added to topo store.", entity.Name)
+			return reconcile.Result{}, nil
+		}
+		if entity.Status.LastSyncTime != nil {
+			if elapsed := time.Since(entity.Status.LastSyncTime.Time); elapsed < r.resyncInterval {
+				log.Debugf("Entity %s is already added to topo store.", entity.Name)
+				return reconcile.Result{RequeueAfter: r.resyncInterval - elapsed}, nil
+			}
+		}
+	}
+
+	// Wait for the objects a modified entity depends on to be added to topo
+	if modified {
+		if message, err := dependencies.CheckKind(ctx, r.client, entity.Namespace, entity.Spec.Kind); err != nil {
+			log.Warnf("Failed to reconcile dependencies of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		} else if message != "" {
+			return r.waitForDependencies(ctx, entity, v1beta1.ReasonWaitingForDependencies, message)
+		}
+		if len(resolved.Unresolved) > 0 {
+			return r.waitForDependencies(ctx, entity, v1beta1.ReasonUnresolvedReference, strings.Join(resolved.Unresolved, "; "))
+		}
+		// Check the aspects of the entity against the aspect schemas declared by its kind
+		if errs, err := r.validateAspects(ctx, entity, resolved); err != nil {
+			log.Warnf("Failed to reconcile aspects of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		} else if len(errs) > 0 {
+			return r.invalidAspects(ctx, entity, errs.ToAggregate())
+		}
+	}
+
+	// Connect to the topology service
+	conn, err := r.conns.Connect(ctx, topoService)
+	if err != nil {
+		log.Warnf("Failed to reconcile syncing entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return r.syncFailed(ctx, entity, err)
+	}
+	defer conn.Release()
+	client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+
+	// Delete the topo object of the previously applied URI if the URI of the entity has changed; the object is
+	// then created at the new URI
+	migrated := entity.Status.AppliedURI != "" && entity.Status.AppliedURI != entity.Spec.URI
+	if err := r.migrateEntity(ctx, entity, topoService, client); err != nil {
+		log.Warnf("Failed to reconcile migrating entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return r.syncFailed(ctx, entity, err)
+	}
+
+	var drift *v1beta1.Drift
+	object, err := r.entityExists(ctx, entity.Spec.URI, client)
+	if err != nil {
+		log.Warnf("Failed to reconcile syncing entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return r.syncFailed(ctx, entity, err)
+	} else if object == nil {
+		if !migrated {
+			log.Warnf("Entity %s not found in topo store; re-creating", entity.Spec.URI)
+			drift = &v1beta1.Drift{
+				Type:    v1beta1.DriftMissing,
+				Message: fmt.Sprintf("entity %s was not found in topo store", entity.Spec.URI),
+			}
+		}
+		if err := r.createEntity(ctx, entity, resolved.Aspects, client); err != nil && !errors.IsAlreadyExists(err) {
+			log.Warnf("Failed to reconcile re-creating entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return r.syncFailed(ctx, entity, err)
+		}
+	} else if err := owners.Check(object, entity, owners.CanClaim(&entity.Status.ObjectStatus, object, getAppliedURI(entity))); err != nil {
+		return r.ownershipConflict(ctx, entity, err)
+	} else if message := r.entityDrift(entity, resolved.Aspects, object); message != "" {
+		if modified {
+			log.Infof("Applying generation %d of entity %s", entity.Generation, entity.Spec.URI)
+		} else {
+			log.Warnf("Entity %s has drifted from its spec (%s); repairing", entity.Spec.URI, message)
+			drift = &v1beta1.Drift{
+				Type:    v1beta1.DriftModified,
+				Message: message,
+			}
+		}
+		if err := r.updateEntity(ctx, entity, resolved.Aspects, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			log.Warnf("Failed to reconcile updating entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return r.syncFailed(ctx, entity, err)
+		}
+	}
+
+	previous := entity.Status.DeepCopy()
+	entity.Status.AppliedAspects = aspects.Types(resolved.Aspects)
+	entity.Status.AppliedLabels = r.labelMapping.Get(entity)
+	entity.Status.AppliedURI = entity.Spec.URI
+	entity.Status.AppliedReferences = resolved.Versions
+	// Report the aspects written to the topo object by other components; an object that was re-created has none
+	entity.Status.ObservedAspects = nil
+	if object != nil {
+		entity.Status.ObservedAspects = aspects.Observe(object, entity.Spec.ObserveAspects, resolved.Referencing)
+	}
+	conditions.SetDependenciesResolved(&entity.Status.ObjectStatus, entity.Generation, true, v1beta1.ReasonResolved, "")
+	conditions.SetSynced(&entity.Status.ObjectStatus, entity.Generation)
+	if drift != nil {
+		drift.DetectedTime = *entity.Status.LastSyncTime
+		entity.Status.LastDrift = drift
+	}
+
+	// Only update the status if more than the sync time has changed, so resources that are in sync are not written
+	// at every resync; the previous sync time then schedules the next resync
+	synced := entity.Status.DeepCopy()
+	synced.LastSyncTime = previous.LastSyncTime
+	if previous.LastSyncTime != nil && equality.Semantic.DeepEqual(synced, previous) {
+		log.Debugf("Entity %s is in sync with topo store.", entity.Name)
+		return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+	}
+	if err := r.client.Status().Update(ctx, entity); err != nil {
+		log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
 func (r *Reconciler) reconcileDelete(ctx context.Context, entity *v1beta1.Entity) (reconcile.Result, error) {
 	// If the entity has already been finalized, exit reconciliation
 	if !k8s.HasFinalizer(entity, topoFinalizer) {
 		return reconcile.Result{}, nil
 	}
 
-	// Get topo service name
-	topoServiceName := entity.Spec.ServiceName
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, entity.Namespace, entity.Spec.ServiceRef, entity.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
 
 	// Check if topo service is available
-	objectKey := types.NamespacedName{Namespace: entity.Namespace, Name: topoServiceName}
-	topoServiceObject := &corev1.Service{}
-	if err := r.client.Get(ctx, objectKey, topoServiceObject); err != nil && k8serrors.IsNotFound(err) {
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
 		// Remove the finalizer if topo service is not found (deleted).
 		k8s.RemoveFinalizer(entity, topoFinalizer)
 		if err := r.client.Update(ctx, entity); err != nil {
 			log.Warnf("Failed to reconcile updating entity %s, %s, %s", entity.Name, entity.Namespace, err)
 			return reconcile.Result{}, err
 		}
-		log.Warnf("Failed to find topo service %s in namespace %s, %s; removed entities' finalizer", topoServiceName, entity.Namespace, err)
+		log.Warnf("Failed to find topo service %s in namespace %s, %s; removed entities' finalizer", topoService.Name, topoService.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// Check if the topo service may be used from the entity's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, entity.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
 		return reconcile.Result{}, err
+	} else if !ok {
+		// Remove the finalizer without deleting the object if the topo service does not allow the namespace.
+		log.Warnf("Topo service %s does not allow namespace %s; removing entity's finalizer", topoService, entity.Namespace)
+		k8s.RemoveFinalizer(entity, topoFinalizer)
+		if err := r.client.Update(ctx, entity); err != nil {
+			log.Warnf("Failed to reconcile removing finalizer of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
 	}
 
 	switch entity.Status.State {
-	case v1beta1.StateAdding, v1beta1.StateAdded:
-		entity.Status = v1beta1.EntityStatus{State: v1beta1.StateRemoving}
+	case v1beta1.StatePending, v1beta1.StateAdding, v1beta1.StateAdded:
+		conditions.SetState(&entity.Status.ObjectStatus, v1beta1.StateRemoving, entity.Generation)
 		err := r.client.Status().Update(ctx, entity)
 		if err != nil {
 			log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
@@ -218,19 +551,26 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, entity *v1beta1.Entity
 		return reconcile.Result{}, nil
 	case v1beta1.StateRemoving:
 		// Connect to the topology service
-		conn, err := grpc.ConnectService(r.client, entity.Namespace, topoServiceName)
+		conn, err := r.conns.Connect(ctx, topoService)
 		if err != nil {
 			log.Warnf("Failed to reconcile deleting entity %s, %s, %s", entity.Name, entity.Namespace, err)
-			return reconcile.Result{}, err
+			return r.syncFailed(ctx, entity, err)
 		}
-		defer conn.Close()
-		client := topo.NewTopoClient(conn)
-		// Delete the entity from the topology
-		if err := r.deleteEntity(ctx, entity, client); err != nil && !errors.IsNotFound(err) {
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Delete, retain or orphan the topo object of the entity unless it is owned by another resource
+		if object, err := r.entityExists(ctx, getAppliedURI(entity), client); err != nil {
 			log.Warnf("Failed to reconcile deleting entity %s, %s, %s", entity.Name, entity.Namespace, err)
-			return reconcile.Result{}, err
+			return r.syncFailed(ctx, entity, err)
+		} else if object != nil {
+			if err := owners.Check(object, entity, owners.CanClaim(&entity.Status.ObjectStatus, object, getAppliedURI(entity))); err != nil {
+				log.Warnf("Not deleting entity %s from topo store, %s", entity.Name, err)
+			} else if err := r.releaseEntity(ctx, entity, object, client); err != nil && !errors.IsNotFound(err) {
+				log.Warnf("Failed to reconcile deleting entity %s, %s, %s", entity.Name, entity.Namespace, err)
+				return r.syncFailed(ctx, entity, err)
+			}
 		}
-		entity.Status = v1beta1.EntityStatus{State: v1beta1.StateRemoved}
+		conditions.SetState(&entity.Status.ObjectStatus, v1beta1.StateRemoved, entity.Generation)
 		err = r.client.Status().Update(ctx, entity)
 		if err != nil {
 			log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
@@ -250,9 +590,70 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, entity *v1beta1.Entity
 	return reconcile.Result{}, nil
 }
 
-func (r *Reconciler) entityExists(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) (*topo.Object, error) {
+// syncFailed records a failure to synchronize the entity with the topo store in its status
+func (r *Reconciler) syncFailed(ctx context.Context, entity *v1beta1.Entity, err error) (reconcile.Result, error) {
+	conditions.SetSyncFailed(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonSyncFailed, err)
+	if err := r.client.Status().Update(ctx, entity); err != nil {
+		log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+	}
+	return reconcile.Result{}, err
+}
+
+// ownershipConflict records in its status that the topo object of the entity is owned by another resource
+func (r *Reconciler) ownershipConflict(ctx context.Context, entity *v1beta1.Entity, err error) (reconcile.Result, error) {
+	log.Warnf("Failed to reconcile entity %s, %s, %s", entity.Name, entity.Namespace, err)
+	conditions.SetSyncFailed(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonOwnershipConflict, err)
+	if err := r.client.Status().Update(ctx, entity); err != nil {
+		log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
+// waitForDependencies records in its status that the entity is waiting for the objects it depends on
+func (r *Reconciler) waitForDependencies(ctx context.Context, entity *v1beta1.Entity, reason, message string) (reconcile.Result, error) {
+	log.Infof("Entity %s is waiting for dependencies: %s", entity.Name, message)
+	conditions.SetDependenciesResolved(&entity.Status.ObjectStatus, entity.Generation, false, reason, message)
+	if err := r.client.Status().Update(ctx, entity); err != nil {
+		log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// invalidAspects records in its status that the aspects of the entity do not conform to the aspect schemas of its kind.
+// The entity is reconciled again when it or its kind is updated.
+func (r *Reconciler) invalidAspects(ctx context.Context, entity *v1beta1.Entity, err error) (reconcile.Result, error) {
+	log.Warnf("Entity %s has invalid aspects: %s", entity.Name, err)
+	conditions.SetSyncFailed(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonInvalidAspects, err)
+	if err := r.client.Status().Update(ctx, entity); err != nil {
+		log.Warnf("Failed to reconcile updating state of entity %s, %s, %s", entity.Name, entity.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// validateAspects validates the resolved aspects of the entity against the entity aspect schemas declared by its
+// kind. Aspects with values referenced in Secrets or ConfigMaps are also validated against their protobuf types,
+// since they can only be validated by the webhook once resolved.
+func (r *Reconciler) validateAspects(ctx context.Context, entity *v1beta1.Entity, resolved *aspects.Resolved) (field.ErrorList, error) {
+	path := field.NewPath("spec", "aspects")
+	var errs field.ErrorList
+	for _, aspectType := range resolved.Referencing {
+		if value, ok := resolved.Aspects[aspectType]; ok {
+			errs = append(errs, aspects.ValidateAspect(path.Key(aspectType), aspectType, value.Raw)...)
+		}
+	}
+	kind, err := dependencies.GetKind(ctx, r.client, entity.Namespace, entity.Spec.Kind)
+	if err != nil || kind == nil {
+		return errs, err
+	}
+	return append(errs, aspects.ValidateSchemas(path, kind.Name, kind.Spec.EntityAspects, resolved.Aspects)...), nil
+}
+
+func (r *Reconciler) entityExists(ctx context.Context, uri string, client topo.TopoClient) (*topo.Object, error) {
 	request := &topo.GetRequest{
-		ID: topo.ID(entity.Spec.URI),
+		ID: topo.ID(uri),
 	}
 	resp, err := client.Get(ctx, request)
 	if err == nil {
@@ -271,7 +672,25 @@ func (r *Reconciler) entityExists(ctx context.Context, entity *v1beta1.Entity, c
 	return nil, nil
 }
 
-func (r *Reconciler) createEntity(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) error {
+// entityDrift returns a description of the differences between the entity spec and the topo object
+func (r *Reconciler) entityDrift(entity *v1beta1.Entity, values map[string]runtime.RawExtension, object *topo.Object) string {
+	var diffs []string
+	if !owners.IsOwner(object, entity) {
+		diffs = append(diffs, "owner")
+	}
+	if object.GetEntity() == nil || object.GetEntity().KindID != topo.ID(entity.Spec.Kind.Name) {
+		diffs = append(diffs, "kind")
+	}
+	if aspectTypes := aspects.Diff(object, values, entity.Status.AppliedAspects); len(aspectTypes) > 0 {
+		diffs = append(diffs, fmt.Sprintf("aspects %s", strings.Join(aspectTypes, ", ")))
+	}
+	if keys := labels.Diff(object, r.labelMapping.Get(entity), entity.Status.AppliedLabels); len(keys) > 0 {
+		diffs = append(diffs, fmt.Sprintf("labels %s", strings.Join(keys, ", ")))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func (r *Reconciler) createEntity(ctx context.Context, entity *v1beta1.Entity, values map[string]runtime.RawExtension, client topo.TopoClient) error {
 	object := &topo.Object{
 		ID:   topo.ID(entity.Spec.URI),
 		Type: topo.Object_ENTITY,
@@ -282,12 +701,14 @@ func (r *Reconciler) createEntity(ctx context.Context, entity *v1beta1.Entity, c
 		},
 		Aspects: make(map[string]*prototypes.Any),
 	}
-	for key, value := range entity.Spec.Aspects {
+	for key, value := range values {
 		err := object.SetAspectBytes(key, value.Raw)
 		if err != nil {
 			return err
 		}
 	}
+	labels.Apply(object, r.labelMapping.Get(entity), nil)
+	owners.SetOwner(object, entity)
 	log.Infof("Creating entity %+v", object)
 	request := &topo.CreateRequest{
 		Object: object,
@@ -312,13 +733,21 @@ func (r *Reconciler) createEntity(ctx context.Context, entity *v1beta1.Entity, c
 	return nil
 }
 
-func (r *Reconciler) updateEntity(ctx context.Context, entity *v1beta1.Entity, object *topo.Object, client topo.TopoClient) error {
-	for key, value := range entity.Spec.Aspects {
-		err := object.SetAspectBytes(key, value.Raw)
-		if err != nil {
-			return err
+func (r *Reconciler) updateEntity(ctx context.Context, entity *v1beta1.Entity, values map[string]runtime.RawExtension, object *topo.Object, client topo.TopoClient) error {
+	if object.GetEntity() != nil {
+		object.GetEntity().KindID = topo.ID(entity.Spec.Kind.Name)
+	} else {
+		object.Obj = &topo.Object_Entity{
+			Entity: &topo.Entity{
+				KindID: topo.ID(entity.Spec.Kind.Name),
+			},
 		}
 	}
+	if err := aspects.Apply(object, values, entity.Status.AppliedAspects); err != nil {
+		return err
+	}
+	labels.Apply(object, r.labelMapping.Get(entity), entity.Status.AppliedLabels)
+	owners.SetOwner(object, entity)
 	log.Infof("Updating entity %+v", object)
 
 	request := &topo.UpdateRequest{
@@ -344,9 +773,89 @@ func (r *Reconciler) updateEntity(ctx context.Context, entity *v1beta1.Entity, o
 	return nil
 }
 
-func (r *Reconciler) deleteEntity(ctx context.Context, entity *v1beta1.Entity, client topo.TopoClient) error {
+// releaseEntity deletes the topo object of the entity, or leaves it in the topo store if the entity's deletion policy
+// is Retain or Orphan. Orphaned objects are released by the entity so that they can be claimed by another resource.
+func (r *Reconciler) releaseEntity(ctx context.Context, entity *v1beta1.Entity, object *topo.Object, client topo.TopoClient) error {
+	switch entity.Spec.DeletionPolicy {
+	case v1beta1.DeletionPolicyRetain:
+		log.Infof("Retaining entity %s", object.ID)
+		return nil
+	case v1beta1.DeletionPolicyOrphan:
+		owners.RemoveOwner(object)
+		log.Infof("Orphaning entity %s", object.ID)
+		_, err := client.Update(ctx, &topo.UpdateRequest{Object: object})
+		return errors.FromGRPC(err)
+	}
+	return r.deleteEntity(ctx, object.ID, client)
+}
+
+// migrateEntity deletes the topo object at the previously applied URI of the entity if its URI has changed, unless
+// the object is owned by another resource. The object is created at the new URI by the caller.
+func (r *Reconciler) migrateEntity(ctx context.Context, entity *v1beta1.Entity, topoService types.NamespacedName, client topo.TopoClient) error {
+	uri := getAppliedURI(entity)
+	if uri == entity.Spec.URI {
+		return nil
+	}
+	log.Infof("Migrating entity %s from %s to %s", entity.Name, uri, entity.Spec.URI)
+	if object, err := r.entityExists(ctx, uri, client); err != nil {
+		return err
+	} else if object != nil {
+		if err := owners.Check(object, entity, owners.CanClaim(&entity.Status.ObjectStatus, object, getAppliedURI(entity))); err != nil {
+			log.Warnf("Not deleting entity %s from topo store, %s", uri, err)
+		} else if err := r.deleteEntity(ctx, object.ID, client); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return r.migrateRelations(ctx, topoService, uri, entity.Spec.URI)
+}
+
+// migrateRelations points the Relations using the given topo service whose source or target is the previous URI
+// of an entity at the new URI of the entity
+func (r *Reconciler) migrateRelations(ctx context.Context, topoService types.NamespacedName, oldURI, newURI string) error {
+	relationList := &v1beta1.RelationList{}
+	if err := r.client.List(ctx, relationList); err != nil {
+		return err
+	}
+	for _, relation := range relationList.Items {
+		if relation.Spec.Source.URI != oldURI && relation.Spec.Target.URI != oldURI {
+			continue
+		}
+		// Mirrored relations reflect topo, and the members of topologies and fabrics are updated by their owner
+		if mirrors.IsMirrored(&relation) || metav1.GetControllerOf(&relation) != nil || relation.DeletionTimestamp != nil {
+			continue
+		}
+		relationService, err := services.GetServiceRef(ctx, r.client, relation.Namespace, relation.Spec.ServiceRef, relation.Spec.ServiceName)
+		if err != nil {
+			return err
+		} else if relationService != topoService {
+			continue
+		}
+		if relation.Spec.Source.URI == oldURI {
+			relation.Spec.Source.URI = newURI
+		}
+		if relation.Spec.Target.URI == oldURI {
+			relation.Spec.Target.URI = newURI
+		}
+		log.Infof("Updating relation %s.%s from %s to %s", relation.Name, relation.Namespace, oldURI, newURI)
+		if err := r.client.Update(ctx, &relation); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAppliedURI returns the URI of the topo object of the entity, which is the previously applied URI until a change
+// of the URI in the spec of the entity has been applied
+func getAppliedURI(entity *v1beta1.Entity) string {
+	if entity.Status.AppliedURI != "" {
+		return entity.Status.AppliedURI
+	}
+	return entity.Spec.URI
+}
+
+func (r *Reconciler) deleteEntity(ctx context.Context, id topo.ID, client topo.TopoClient) error {
 	request := &topo.DeleteRequest{
-		ID: topo.ID(entity.Spec.URI),
+		ID: id,
 	}
 	log.Infof("Deleting entity %s", request.ID)
 
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fabric
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"text/template"
+)
+
+const (
+	roleSpine = "spine"
+	roleLeaf  = "leaf"
+	rolePort  = "port"
+	roleLink  = "link"
+)
+
+// object is the data the aspect templates of the objects of a fabric are rendered with
+type object struct {
+	// Fabric is the name of the FabricTemplate
+	Fabric string
+	// Name is the name of the Entity
+	Name string
+	// URI is the URI of the Entity
+	URI string
+	// Role is the role of the switch: spine or leaf, or the role of the switch a port belongs to
+	Role string
+	// Index is the index of the switch within its role, starting at 1
+	Index int32
+	// Port is the number of the port, starting at 1
+	Port int32
+	// Source is the URI of the port a link originates from
+	Source string
+	// Target is the URI of the port a link terminates at
+	Target string
+}
+
+// fabric is the expansion of a FabricTemplate into Entities and Relations
+type fabric struct {
+	template  *v1beta1.FabricTemplate
+	aspects   map[string]map[string]*template.Template
+	switches  int32
+	links     int32
+	entities  []v1beta1.EntityTemplate
+	relations []v1beta1.RelationTemplate
+}
+
+// expand generates the Entities and Relations of the given FabricTemplate. The generated names and URIs only
+// depend on the role and index of each switch and the number of each port, so that scaling the fabric only adds or
+// removes the objects of the added or removed switches and their links.
+func expand(fabricTemplate *v1beta1.FabricTemplate) (*fabric, error) {
+	spec := fabricTemplate.Spec
+	if spec.LinkPattern == v1beta1.LinkPatternFullMesh && (spec.PortsPerSwitch < spec.Spines || spec.PortsPerSwitch < spec.Leaves) {
+		return nil, fmt.Errorf("portsPerSwitch %d is too small to link %d leaves to %d spines", spec.PortsPerSwitch, spec.Leaves, spec.Spines)
+	}
+
+	f := &fabric{
+		template: fabricTemplate,
+		aspects:  make(map[string]map[string]*template.Template),
+	}
+	for role, aspects := range map[string]map[string]runtime.RawExtension{
+		roleSpine: spec.Aspects.Spine,
+		roleLeaf:  spec.Aspects.Leaf,
+		rolePort:  spec.Aspects.Port,
+		roleLink:  spec.Aspects.Link,
+	} {
+		templates, err := parseAspects(role, aspects)
+		if err != nil {
+			return nil, err
+		}
+		f.aspects[role] = templates
+	}
+
+	for i := int32(1); i <= spec.Spines; i++ {
+		if err := f.addSwitch(roleSpine, i); err != nil {
+			return nil, err
+		}
+	}
+	for i := int32(1); i <= spec.Leaves; i++ {
+		if err := f.addSwitch(roleLeaf, i); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.LinkPattern == v1beta1.LinkPatternFullMesh {
+		// Port s of leaf l is connected to port l of spine s
+		for l := int32(1); l <= spec.Leaves; l++ {
+			for s := int32(1); s <= spec.Spines; s++ {
+				leafPort := f.getPort(roleLeaf, l, s)
+				spinePort := f.getPort(roleSpine, s, l)
+				if err := f.addLink(leafPort, spinePort); err != nil {
+					return nil, err
+				}
+				if err := f.addLink(spinePort, leafPort); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return f, nil
+}
+
+// parseAspects parses the aspect templates of the given role
+func parseAspects(role string, aspects map[string]runtime.RawExtension) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	for name, aspect := range aspects {
+		t, err := template.New(name).Option("missingkey=error").Parse(string(aspect.Raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s aspect %s: %s", role, name, err)
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+// renderAspects renders the aspect templates of the given role for the given object
+func (f *fabric) renderAspects(role string, o object) (map[string]runtime.RawExtension, error) {
+	templates := f.aspects[role]
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	aspects := make(map[string]runtime.RawExtension)
+	for name, t := range templates {
+		buf := &bytes.Buffer{}
+		if err := t.Execute(buf, o); err != nil {
+			return nil, fmt.Errorf("failed to render %s aspect %s of %s: %s", role, name, o.Name, err)
+		}
+		if !json.Valid(buf.Bytes()) {
+			return nil, fmt.Errorf("%s aspect %s of %s is not valid JSON", role, name, o.Name)
+		}
+		aspects[name] = runtime.RawExtension{Raw: buf.Bytes()}
+	}
+	return aspects, nil
+}
+
+// getSwitch returns the name and URI of the switch with the given role and index
+func (f *fabric) getSwitch(role string, index int32) object {
+	name := fmt.Sprintf("%s-%s-%d", f.template.Name, role, index)
+	return object{
+		Fabric: f.template.Name,
+		Name:   name,
+		URI:    fmt.Sprintf("%s:%s", f.template.Spec.Protocol, name),
+		Role:   role,
+		Index:  index,
+	}
+}
+
+// getPort returns the name and URI of the given port of the switch with the given role and index
+func (f *fabric) getPort(role string, index int32, port int32) object {
+	o := f.getSwitch(role, index)
+	o.Name = fmt.Sprintf("%s-%d", o.Name, port)
+	o.URI = fmt.Sprintf("%s/%d/0", o.URI, port)
+	o.Port = port
+	return o
+}
+
+// addSwitch adds the Entities of the switch with the given role and index and of its ports
+func (f *fabric) addSwitch(role string, index int32) error {
+	o := f.getSwitch(role, index)
+	if err := f.addEntity(role, o, f.template.Spec.Kinds.Switch); err != nil {
+		return err
+	}
+	f.switches++
+	for port := int32(1); port <= f.template.Spec.PortsPerSwitch; port++ {
+		if err := f.addEntity(rolePort, f.getPort(role, index, port), f.template.Spec.Kinds.Port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addLink adds the Entity of a link from the given source port to the given target port and the Relations
+// connecting the link to the ports
+func (f *fabric) addLink(source, target object) error {
+	o := object{
+		Fabric: f.template.Name,
+		Name:   fmt.Sprintf("%s-%s", source.Name, target.Name),
+		URI:    fmt.Sprintf("%s-%s", source.URI, target.URI),
+		Source: source.URI,
+		Target: target.URI,
+	}
+	if err := f.addEntity(roleLink, o, f.template.Spec.Kinds.Link); err != nil {
+		return err
+	}
+	f.links++
+	f.addRelation(fmt.Sprintf("%s-originates", o.Name), fmt.Sprintf("%s/originates", o.URI), f.template.Spec.Kinds.Originates, source, o)
+	f.addRelation(fmt.Sprintf("%s-terminates", o.Name), fmt.Sprintf("%s/terminates", o.URI), f.template.Spec.Kinds.Terminates, target, o)
+	return nil
+}
+
+func (f *fabric) addEntity(role string, o object, kind string) error {
+	aspects, err := f.renderAspects(role, o)
+	if err != nil {
+		return err
+	}
+	f.entities = append(f.entities, v1beta1.EntityTemplate{
+		Name: o.Name,
+		Spec: v1beta1.EntitySpec{
+			URI: o.URI,
+			Kind: metav1.ObjectMeta{
+				Name: kind,
+			},
+			Aspects:        aspects,
+			ServiceName:    f.template.Spec.ServiceName,
+			ServiceRef:     f.template.Spec.ServiceRef,
+			DeletionPolicy: f.template.Spec.DeletionPolicy,
+		},
+	})
+	return nil
+}
+
+func (f *fabric) addRelation(name string, uri string, kind string, source, target object) {
+	f.relations = append(f.relations, v1beta1.RelationTemplate{
+		Name: name,
+		Spec: v1beta1.RelationSpec{
+			URI: uri,
+			Kind: metav1.ObjectMeta{
+				Name: kind,
+			},
+			Source: v1beta1.RelationEndpoint{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: source.Name,
+				},
+				URI: source.URI,
+			},
+			Target: v1beta1.RelationEndpoint{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: target.Name,
+				},
+				URI: target.URI,
+			},
+			ServiceName:    f.template.Spec.ServiceName,
+			ServiceRef:     f.template.Spec.ServiceRef,
+			DeletionPolicy: f.template.Spec.DeletionPolicy,
+		},
+	})
+}
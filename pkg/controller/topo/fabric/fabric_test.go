@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fabric
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+)
+
+func newFabricTemplate(spines, leaves, ports int32, pattern v1beta1.LinkPattern) *v1beta1.FabricTemplate {
+	return &v1beta1.FabricTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "fabric",
+		},
+		Spec: v1beta1.FabricTemplateSpec{
+			Spines:         spines,
+			Leaves:         leaves,
+			PortsPerSwitch: ports,
+			LinkPattern:    pattern,
+			Protocol:       "p4rt",
+			Kinds: v1beta1.FabricKinds{
+				Switch:     "switch",
+				Port:       "port",
+				Link:       "link",
+				Originates: "originates",
+				Terminates: "terminates",
+			},
+		},
+	}
+}
+
+// TestExpand verifies the number of objects generated for a fabric and that full mesh fabrics require a port on
+// every switch for each switch of the other role
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  *v1beta1.FabricTemplate
+		err       bool
+		switches  int32
+		links     int32
+		entities  int
+		relations int
+	}{
+		{
+			name:      "full mesh",
+			template:  newFabricTemplate(2, 4, 4, v1beta1.LinkPatternFullMesh),
+			switches:  6,
+			links:     16,
+			entities:  6 + 6*4 + 16,
+			relations: 32,
+		},
+		{
+			name:     "no links",
+			template: newFabricTemplate(2, 4, 1, v1beta1.LinkPatternNone),
+			switches: 6,
+			entities: 6 + 6*1,
+		},
+		{
+			name:     "too few ports for leaves",
+			template: newFabricTemplate(2, 4, 3, v1beta1.LinkPatternFullMesh),
+			err:      true,
+		},
+		{
+			name:     "too few ports for spines",
+			template: newFabricTemplate(4, 2, 3, v1beta1.LinkPatternFullMesh),
+			err:      true,
+		},
+		{
+			name:     "empty",
+			template: newFabricTemplate(0, 0, 4, v1beta1.LinkPatternFullMesh),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := expand(test.template)
+			if test.err {
+				if err == nil {
+					t.Fatal("expected expansion to fail")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if f.switches != test.switches {
+				t.Errorf("expected %d switches, got %d", test.switches, f.switches)
+			}
+			if f.links != test.links {
+				t.Errorf("expected %d links, got %d", test.links, f.links)
+			}
+			if len(f.entities) != test.entities {
+				t.Errorf("expected %d entities, got %d", test.entities, len(f.entities))
+			}
+			if len(f.relations) != test.relations {
+				t.Errorf("expected %d relations, got %d", test.relations, len(f.relations))
+			}
+		})
+	}
+}
+
+// TestExpandNames verifies the names and URIs of the objects generated for a fabric
+func TestExpandNames(t *testing.T) {
+	f, err := expand(newFabricTemplate(1, 1, 1, v1beta1.LinkPatternFullMesh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entities := make(map[string]v1beta1.EntityTemplate)
+	for _, entity := range f.entities {
+		entities[entity.Name] = entity
+	}
+	relations := make(map[string]v1beta1.RelationTemplate)
+	for _, relation := range f.relations {
+		relations[relation.Name] = relation
+	}
+
+	tests := []struct {
+		name string
+		uri  string
+		kind string
+	}{
+		{name: "fabric-spine-1", uri: "p4rt:fabric-spine-1", kind: "switch"},
+		{name: "fabric-leaf-1", uri: "p4rt:fabric-leaf-1", kind: "switch"},
+		{name: "fabric-spine-1-1", uri: "p4rt:fabric-spine-1/1/0", kind: "port"},
+		{name: "fabric-leaf-1-1", uri: "p4rt:fabric-leaf-1/1/0", kind: "port"},
+		{name: "fabric-leaf-1-1-fabric-spine-1-1", uri: "p4rt:fabric-leaf-1/1/0-p4rt:fabric-spine-1/1/0", kind: "link"},
+		{name: "fabric-spine-1-1-fabric-leaf-1-1", uri: "p4rt:fabric-spine-1/1/0-p4rt:fabric-leaf-1/1/0", kind: "link"},
+	}
+	if len(entities) != len(tests) {
+		t.Errorf("expected %d entities, got %d", len(tests), len(entities))
+	}
+	for _, test := range tests {
+		entity, ok := entities[test.name]
+		if !ok {
+			t.Errorf("expected entity %s", test.name)
+			continue
+		}
+		if entity.Spec.URI != test.uri {
+			t.Errorf("expected URI of entity %s to be %s, got %s", test.name, test.uri, entity.Spec.URI)
+		}
+		if entity.Spec.Kind.Name != test.kind {
+			t.Errorf("expected kind of entity %s to be %s, got %s", test.name, test.kind, entity.Spec.Kind.Name)
+		}
+	}
+
+	// Each link originates from the port of its source switch and terminates at the port of its target switch
+	link := "fabric-leaf-1-1-fabric-spine-1-1"
+	originates, ok := relations[link+"-originates"]
+	if !ok {
+		t.Fatalf("expected relation %s-originates", link)
+	}
+	if originates.Spec.URI != "p4rt:fabric-leaf-1/1/0-p4rt:fabric-spine-1/1/0/originates" {
+		t.Errorf("unexpected URI %s", originates.Spec.URI)
+	}
+	if originates.Spec.Source.Name != "fabric-leaf-1-1" || originates.Spec.Target.Name != link {
+		t.Errorf("expected %s to originate from fabric-leaf-1-1, got %s -> %s", link, originates.Spec.Source.Name, originates.Spec.Target.Name)
+	}
+	terminates, ok := relations[link+"-terminates"]
+	if !ok {
+		t.Fatalf("expected relation %s-terminates", link)
+	}
+	if terminates.Spec.Source.Name != "fabric-spine-1-1" || terminates.Spec.Target.Name != link {
+		t.Errorf("expected %s to terminate at fabric-spine-1-1, got %s -> %s", link, terminates.Spec.Source.Name, terminates.Spec.Target.Name)
+	}
+}
+
+// TestExpandScale verifies that scaling a fabric only adds or removes the objects of the added or removed switches
+// and their links
+func TestExpandScale(t *testing.T) {
+	names := func(leaves int32) map[string]bool {
+		f, err := expand(newFabricTemplate(2, leaves, 4, v1beta1.LinkPatternFullMesh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make(map[string]bool)
+		for _, entity := range f.entities {
+			names[entity.Name] = true
+		}
+		for _, relation := range f.relations {
+			names[relation.Name] = true
+		}
+		return names
+	}
+	difference := func(a, b map[string]bool) map[string]bool {
+		diff := make(map[string]bool)
+		for name := range a {
+			if !b[name] {
+				diff[name] = true
+			}
+		}
+		return diff
+	}
+
+	// The objects of leaves 3 and 4: the switches, their 4 ports, their 2 links to and from each spine and the
+	// 2 relations of each link
+	expected := make(map[string]bool)
+	for l := 3; l <= 4; l++ {
+		leaf := fmt.Sprintf("fabric-leaf-%d", l)
+		expected[leaf] = true
+		for p := 1; p <= 4; p++ {
+			expected[fmt.Sprintf("%s-%d", leaf, p)] = true
+		}
+		for s := 1; s <= 2; s++ {
+			leafPort := fmt.Sprintf("%s-%d", leaf, s)
+			spinePort := fmt.Sprintf("fabric-spine-%d-%d", s, l)
+			for _, link := range []string{leafPort + "-" + spinePort, spinePort + "-" + leafPort} {
+				expected[link] = true
+				expected[link+"-originates"] = true
+				expected[link+"-terminates"] = true
+			}
+		}
+	}
+
+	two, four := names(2), names(4)
+	if removed := difference(two, four); len(removed) != 0 {
+		t.Errorf("expected scaling up not to remove objects, removed %v", removed)
+	}
+	if added := difference(four, two); !equalSets(added, expected) {
+		t.Errorf("expected scaling up to add %v, added %v", expected, added)
+	}
+	if removed := difference(four, names(2)); !equalSets(removed, expected) {
+		t.Errorf("expected scaling down to remove %v, removed %v", expected, removed)
+	}
+}
+
+// TestExpandAspects verifies the rendering of the aspect templates of a fabric
+func TestExpandAspects(t *testing.T) {
+	tests := []struct {
+		name     string
+		aspect   string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "rendered",
+			aspect:   `{"index":{{.Index}},"uri":"{{.URI}}"}`,
+			expected: `{"index":1,"uri":"p4rt:fabric-leaf-1"}`,
+		},
+		{
+			name:   "missing key",
+			aspect: `{"rack":"{{.Rack}}"}`,
+			err:    true,
+		},
+		{
+			name:   "invalid JSON",
+			aspect: `{"index":{{.Index}}`,
+			err:    true,
+		},
+		{
+			name:   "invalid template",
+			aspect: `{"index":{{.Index}`,
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			template := newFabricTemplate(0, 1, 0, v1beta1.LinkPatternNone)
+			template.Spec.Aspects.Leaf = map[string]runtime.RawExtension{
+				"onos.topo.Switch": {Raw: []byte(test.aspect)},
+			}
+			f, err := expand(template)
+			if test.err {
+				if err == nil {
+					t.Fatal("expected expansion to fail")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			aspect := string(f.entities[0].Spec.Aspects["onos.topo.Switch"].Raw)
+			if aspect != test.expected {
+				t.Errorf("expected aspect %s, got %s", test.expected, aspect)
+			}
+		})
+	}
+}
+
+func equalSets(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if !b[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fabric
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/members"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+)
+
+var log = logging.GetLogger("controller", "topo", "fabric")
+
+// FabricLabel is the label recording the name of the FabricTemplate an Entity or Relation was generated from
+const FabricLabel = "topo.onosproject.org/fabric"
+
+// Add creates a new FabricTemplate controller and adds it to the Manager. The Manager will set fields on the
+// controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := &Reconciler{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		resyncInterval: k8s.GetResyncInterval(),
+	}
+
+	// Create a new controller
+	c, err := controller.New("topo-fabric-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource FabricTemplate, ignoring updates to the FabricTemplate status
+	err = c.Watch(&source.Kind{Type: &v1beta1.FabricTemplate{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the generated resources and requeue the owning FabricTemplate
+	for _, object := range []client.Object{&v1beta1.Entity{}, &v1beta1.Relation{}} {
+		err = c.Watch(&source.Kind{Type: object}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &v1beta1.FabricTemplate{},
+			IsController: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciler reconciles a FabricTemplate object
+type Reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	config *rest.Config
+	// resyncInterval is the interval at which FabricTemplates with failed resources are retried
+	resyncInterval time.Duration
+}
+
+// Reconcile reads that state of the cluster for a FabricTemplate object and makes changes based on the state read
+// and what is in the FabricTemplate.Spec
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log.Infof("Reconciling FabricTemplate %s.%s", request.Namespace, request.Name)
+
+	// Fetch the FabricTemplate instance
+	fabricTemplate := &v1beta1.FabricTemplate{}
+	err := r.client.Get(ctx, request.NamespacedName, fabricTemplate)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The resources generated for deleted FabricTemplates are garbage collected
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if fabricTemplate.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	f, err := expand(fabricTemplate)
+	if err != nil {
+		// Leave the resources generated for the previous generation in place until the template is fixed
+		log.Warnf("Failed to expand FabricTemplate %s.%s, %s", fabricTemplate.Name, fabricTemplate.Namespace, err)
+		status := fabricTemplate.Status.DeepCopy()
+		status.ObservedGeneration = fabricTemplate.Generation
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               v1beta1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: fabricTemplate.Generation,
+			Reason:             v1beta1.ReasonInvalidTemplate,
+			Message:            err.Error(),
+		})
+		return reconcile.Result{}, r.updateStatus(ctx, fabricTemplate, status)
+	}
+
+	p := r.apply(ctx, fabricTemplate, f)
+	if err := r.prune(ctx, fabricTemplate, f); err != nil {
+		log.Warnf("Failed to prune FabricTemplate %s.%s, %s", fabricTemplate.Name, fabricTemplate.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	status := fabricTemplate.Status.DeepCopy()
+	status.ObservedGeneration = fabricTemplate.Generation
+	status.Switches = f.switches
+	status.Links = f.links
+	status.Members = p.Members
+	status.Applied = p.Applied
+	status.Failed = int32(len(p.Failures))
+	status.Progress = p.GetProgress()
+	status.Failures = p.GetFailures()
+	meta.SetStatusCondition(&status.Conditions, p.GetReadyCondition(fabricTemplate.Generation))
+	if err := r.updateStatus(ctx, fabricTemplate, status); err != nil {
+		log.Warnf("Failed to update status of FabricTemplate %s.%s, %s", fabricTemplate.Name, fabricTemplate.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	if len(p.Failures) > 0 {
+		return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// apply creates or updates the Entities of the given fabric, and the Relations whose ports and link have been
+// added to topo
+func (r *Reconciler) apply(ctx context.Context, fabricTemplate *v1beta1.FabricTemplate, f *fabric) *members.Progress {
+	p := &members.Progress{
+		Members: int32(len(f.entities) + len(f.relations)),
+	}
+
+	entities := make(map[string]bool)
+	for _, template := range f.entities {
+		entity, err := members.ApplyEntity(ctx, r.client, r.scheme, fabricTemplate, FabricLabel, template)
+		if err != nil {
+			entities[template.Name] = p.Record("Entity", template.Name, nil, err)
+		} else {
+			entities[template.Name] = p.Record("Entity", template.Name, &entity.Status.ObjectStatus, nil)
+		}
+	}
+
+	for _, template := range f.relations {
+		if !entities[template.Spec.Source.Name] || !entities[template.Spec.Target.Name] {
+			continue
+		}
+		relation, err := members.ApplyRelation(ctx, r.client, r.scheme, fabricTemplate, FabricLabel, template)
+		if err != nil {
+			p.Record("Relation", template.Name, nil, err)
+		} else {
+			p.Record("Relation", template.Name, &relation.Status.ObjectStatus, nil)
+		}
+	}
+	return p
+}
+
+// prune deletes the resources that are no longer generated for the given fabric
+func (r *Reconciler) prune(ctx context.Context, fabricTemplate *v1beta1.FabricTemplate, f *fabric) error {
+	relations := make(map[string]bool)
+	for _, template := range f.relations {
+		relations[template.Name] = true
+	}
+	if err := members.Prune(ctx, r.client, fabricTemplate, FabricLabel, &v1beta1.RelationList{}, relations); err != nil {
+		return err
+	}
+
+	entities := make(map[string]bool)
+	for _, template := range f.entities {
+		entities[template.Name] = true
+	}
+	return members.Prune(ctx, r.client, fabricTemplate, FabricLabel, &v1beta1.EntityList{}, entities)
+}
+
+// updateStatus updates the status of the given FabricTemplate if it changed
+func (r *Reconciler) updateStatus(ctx context.Context, fabricTemplate *v1beta1.FabricTemplate, status *v1beta1.FabricTemplateStatus) error {
+	if equality.Semantic.DeepEqual(&fabricTemplate.Status, status) {
+		return nil
+	}
+	fabricTemplate.Status = *status
+	return r.client.Status().Update(ctx, fabricTemplate)
+}
@@ -6,39 +6,61 @@ package kind
 
 import (
 	"context"
+	"fmt"
 	prototypes "github.com/gogo/protobuf/types"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
 	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/labels"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strings"
+	"time"
 )
 
 var log = logging.GetLogger("controller", "topo", "kind")
 
-const topoService = "onos-topo"
 const topoFinalizer = "topo"
 
+// maxDependents is the maximum number of dependents listed in the status of a kind
+const maxDependents = 20
+
 // Add creates a new Kind controller and adds it to the Manager. The Manager will set fields on the
 // controller and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+func Add(mgr manager.Manager, conns *grpc.ConnManager, watcher *watchers.Watcher) error {
 	r := &Reconciler{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		config: mgr.GetConfig(),
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		conns:          conns,
+		watcher:        watcher,
+		resyncInterval: k8s.GetResyncInterval(),
+		labelMapping:   labels.GetMapping(),
 	}
 
 	// Create a new controller
@@ -52,6 +74,78 @@ func Add(mgr manager.Manager) error {
 	if err != nil {
 		return err
 	}
+
+	// Watch for changes to the topo objects of Kind resources made in the topo store
+	err = c.Watch(&source.Channel{Source: watcher.Kinds()}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for Entities and Relations being added or removed and requeue the kinds they depend on
+	err = c.Watch(&source.Kind{Type: &v1beta1.Entity{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		entity := object.(*v1beta1.Entity)
+		return []reconcile.Request{
+			{
+				NamespacedName: dependencies.GetNamespacedName(entity.Namespace, entity.Spec.Kind),
+			},
+		}
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &v1beta1.Relation{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		relation := object.(*v1beta1.Relation)
+		return []reconcile.Request{
+			{
+				NamespacedName: dependencies.GetNamespacedName(relation.Namespace, relation.Spec.Kind),
+			},
+		}
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to topo services and requeue the kind resources that use them
+	serviceHandler := handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		keys, err := services.GetIndexKeys(context.Background(), mgr.GetClient(), client.ObjectKeyFromObject(object))
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, key := range keys {
+			kindList := &v1beta1.KindList{}
+			if err := mgr.GetClient().List(context.Background(), kindList, client.MatchingFields{services.ServiceField: key}); err != nil {
+				log.Error(err)
+				return nil
+			}
+			for _, kind := range kindList.Items {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: kind.Namespace,
+						Name:      kind.Name,
+					},
+				})
+			}
+		}
+		return requests
+	})
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, serviceHandler)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, serviceHandler)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -62,6 +156,13 @@ type Reconciler struct {
 	client client.Client
 	scheme *runtime.Scheme
 	config *rest.Config
+	conns  *grpc.ConnManager
+	// watcher watches topo services for changes to the topo objects of the resources
+	watcher *watchers.Watcher
+	// resyncInterval is the interval at which added kinds are verified against the topo store
+	resyncInterval time.Duration
+	// labelMapping selects the labels and annotations of kinds that are copied to the labels of their topo objects
+	labelMapping labels.Mapping
 }
 
 // Reconcile reads that state of the cluster for a Kind object and makes changes based on the state read
@@ -76,13 +177,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Forget the changes watched for the topo object of the deleted kind.
 			// Return and don't requeue
+			r.watcher.ClearChanged(&v1beta1.Kind{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}})
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	// Mirrored kind resources are managed by the mirror controller
+	if mirrors.IsMirrored(kind) {
+		return reconcile.Result{}, nil
+	}
+
+	// Claim the topo objects added by a previous version of the operator, which did not record ownership
+	if owners.ClaimLegacy(kind, topoFinalizer, &kind.Status.ObjectStatus) {
+		log.Infof("Claiming topo object of kind %s, %s added by a previous version of the operator", kind.Name, kind.Namespace)
+		if err := r.client.Status().Update(ctx, kind); err != nil {
+			log.Warnf("Failed to reconcile claiming kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	if kind.DeletionTimestamp == nil {
 		return r.reconcileCreate(ctx, kind)
 	}
@@ -90,39 +208,191 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcileCreate(ctx context.Context, kind *v1beta1.Kind) (reconcile.Result, error) {
-	// Add the finalizer to the kind if necessary
-	if !k8s.HasFinalizer(kind, topoFinalizer) {
-		k8s.AddFinalizer(kind, topoFinalizer)
-		err := r.client.Update(ctx, kind)
-		if err != nil {
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, kind.Namespace, kind.Spec.ServiceRef, kind.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// Check if topo service is available
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
+		// Set the state to StatePending if topo service is not found (deleted).
+		conditions.SetPending(&kind.Status.ObjectStatus, kind.Generation, v1beta1.ReasonServiceNotFound,
+			fmt.Sprintf("topo service %s not found", topoService))
+		if err := r.client.Status().Update(ctx, kind); err != nil {
+			log.Warnf("Failed to update state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
 			return reconcile.Result{}, err
 		}
+		log.Warnf("Failed to find topo service %s in namespace %s, %s", topoService.Name, topoService.Namespace, err)
+		return reconcile.Result{}, err
 	}
 
-	// Connect to the topology service
-	conn, err := grpc.ConnectService(r.client, kind.Namespace, topoService)
-	if err != nil {
+	// Check if the topo service may be used from the kind's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, kind.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
 		return reconcile.Result{}, err
+	} else if !ok {
+		conditions.SetPending(&kind.Status.ObjectStatus, kind.Generation, v1beta1.ReasonServiceNotAllowed,
+			fmt.Sprintf("topo service %s does not allow namespace %s", topoService, kind.Namespace))
+		if err := r.client.Status().Update(ctx, kind); err != nil {
+			log.Warnf("Failed to update state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		log.Warnf("Topo service %s does not allow namespace %s", topoService, kind.Namespace)
+		return reconcile.Result{}, nil
 	}
-	defer conn.Close()
 
-	client := topo.NewTopoClient(conn)
+	// Watch the topo service for changes to the topo object of the kind
+	r.watcher.Watch(topoService)
 
-	// Check if the kind exists in the topology and return it for update if so
-	if object, err := r.kindExists(ctx, kind, client); err != nil {
-		return reconcile.Result{}, err
-	} else if object != nil {
-		if err := r.updateKind(ctx, kind, object, client); err != nil {
+	switch kind.Status.State {
+	case "", v1beta1.StatePending:
+		conditions.SetState(&kind.Status.ObjectStatus, v1beta1.StateAdding, kind.Generation)
+		err := r.client.Status().Update(ctx, kind)
+		if err != nil {
+			log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
 			return reconcile.Result{}, err
 		}
 		return reconcile.Result{}, nil
+	case v1beta1.StateAdding:
+		// Add the finalizer to the kind if necessary
+		if !k8s.HasFinalizer(kind, topoFinalizer) {
+			k8s.AddFinalizer(kind, topoFinalizer)
+			err := r.client.Update(ctx, kind)
+			if err != nil {
+				log.Warnf("Failed to reconcile adding finalizer to kind %s, %s, %s", kind.Name, kind.Namespace, err)
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, nil
+		}
+		// Connect to the topology service
+		conn, err := r.conns.Connect(ctx, topoService)
+		if err != nil {
+			log.Warnf("Failed to reconcile creating kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		}
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Check if the kind exists in the topology and return it for update if so
+		if object, err := r.kindExists(ctx, kind, client); err != nil {
+			return r.syncFailed(ctx, kind, err)
+		} else if object != nil {
+			if err := owners.Check(object, kind, owners.CanClaim(&kind.Status.ObjectStatus, object, kind.Name)); err != nil {
+				return r.ownershipConflict(ctx, kind, err)
+			}
+			if err := r.updateKind(ctx, kind, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+				log.Warnf("Failed to reconcile creating kind %s, %s, %s", kind.Name, kind.Namespace, err)
+				return r.syncFailed(ctx, kind, err)
+			}
+		} else if err := r.createKind(ctx, kind, client); err != nil && !errors.IsAlreadyExists(err) {
+			log.Warnf("Failed to reconcile creating kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		}
+		kind.Status.AppliedAspects = aspects.Types(kind.Spec.Aspects)
+		kind.Status.AppliedLabels = r.labelMapping.Get(kind)
+		conditions.SetState(&kind.Status.ObjectStatus, v1beta1.StateAdded, kind.Generation)
+		conditions.SetDependenciesResolved(&kind.Status.ObjectStatus, kind.Generation, true, v1beta1.ReasonResolved, "")
+		conditions.SetSynced(&kind.Status.ObjectStatus, kind.Generation)
+		err = r.client.Status().Update(ctx, kind)
+		if err != nil {
+			log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	case v1beta1.StateAdded:
+		return r.reconcileAdded(ctx, kind, topoService)
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileAdded propagates spec changes of an added kind to the topo store and periodically verifies it
+// against the topo store, re-creating or repairing the topo object if it has drifted from the Kind spec
+func (r *Reconciler) reconcileAdded(ctx context.Context, kind *v1beta1.Kind, topoService types.NamespacedName) (reconcile.Result, error) {
+	// If the spec and mapped labels have not changed since they were last applied and the topo object has not
+	// been changed in the topo store, wait for the resync interval to elapse
+	modified := kind.Status.ObservedGeneration != kind.Generation || !labels.Equal(r.labelMapping.Get(kind), kind.Status.AppliedLabels)
+	changed := r.watcher.ClearChanged(kind)
+	if !modified && !changed {
+		if r.resyncInterval == 0 {
+			log.Debugf("Kind %s is already added to topo store.", kind.Name)
+			return reconcile.Result{}, nil
+		}
+		if kind.Status.LastSyncTime != nil {
+			if elapsed := time.Since(kind.Status.LastSyncTime.Time); elapsed < r.resyncInterval {
+				log.Debugf("Kind %s is already added to topo store.", kind.Name)
+				return reconcile.Result{RequeueAfter: r.resyncInterval - elapsed}, nil
+			}
+		}
 	}
 
-	// If the kind does not exist, create it
-	if err := r.createKind(ctx, kind, client); err != nil {
+	// Connect to the topology service
+	conn, err := r.conns.Connect(ctx, topoService)
+	if err != nil {
+		log.Warnf("Failed to reconcile syncing kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return r.syncFailed(ctx, kind, err)
+	}
+	defer conn.Release()
+	client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+
+	var drift *v1beta1.Drift
+	if object, err := r.kindExists(ctx, kind, client); err != nil {
+		log.Warnf("Failed to reconcile syncing kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return r.syncFailed(ctx, kind, err)
+	} else if object == nil {
+		log.Warnf("Kind %s not found in topo store; re-creating", kind.Name)
+		if err := r.createKind(ctx, kind, client); err != nil && !errors.IsAlreadyExists(err) {
+			log.Warnf("Failed to reconcile re-creating kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		}
+		drift = &v1beta1.Drift{
+			Type:    v1beta1.DriftMissing,
+			Message: fmt.Sprintf("kind %s was not found in topo store", kind.Name),
+		}
+	} else if err := owners.Check(object, kind, owners.CanClaim(&kind.Status.ObjectStatus, object, kind.Name)); err != nil {
+		return r.ownershipConflict(ctx, kind, err)
+	} else if message := r.kindDrift(kind, object); message != "" {
+		if modified {
+			log.Infof("Applying generation %d of kind %s", kind.Generation, kind.Name)
+		} else {
+			log.Warnf("Kind %s has drifted from its spec (%s); repairing", kind.Name, message)
+			drift = &v1beta1.Drift{
+				Type:    v1beta1.DriftModified,
+				Message: message,
+			}
+		}
+		if err := r.updateKind(ctx, kind, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			log.Warnf("Failed to reconcile updating kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		}
+	}
+
+	previous := kind.Status.DeepCopy()
+	kind.Status.AppliedAspects = aspects.Types(kind.Spec.Aspects)
+	kind.Status.AppliedLabels = r.labelMapping.Get(kind)
+	conditions.SetSynced(&kind.Status.ObjectStatus, kind.Generation)
+	if drift != nil {
+		drift.DetectedTime = *kind.Status.LastSyncTime
+		kind.Status.LastDrift = drift
+	}
+
+	// Only update the status if more than the sync time has changed, so resources that are in sync are not written
+	// at every resync; the previous sync time then schedules the next resync
+	synced := kind.Status.DeepCopy()
+	synced.LastSyncTime = previous.LastSyncTime
+	if previous.LastSyncTime != nil && equality.Semantic.DeepEqual(synced, previous) {
+		log.Debugf("Kind %s is in sync with topo store.", kind.Name)
+		return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+	}
+	if err := r.client.Status().Update(ctx, kind); err != nil {
+		log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
 }
 
 func (r *Reconciler) reconcileDelete(ctx context.Context, kind *v1beta1.Kind) (reconcile.Result, error) {
@@ -131,39 +401,214 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, kind *v1beta1.Kind) (r
 		return reconcile.Result{}, nil
 	}
 
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, kind.Namespace, kind.Spec.ServiceRef, kind.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// If the namespace is being deleted, a topo service in the same namespace is going away with it
 	ns := &corev1.Namespace{}
 	nsName := types.NamespacedName{
 		Name: kind.Namespace,
 	}
-	err := r.client.Get(ctx, nsName, ns)
+	err = r.client.Get(ctx, nsName, ns)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
+	if topoService.Namespace == kind.Namespace && (err != nil || ns.DeletionTimestamp != nil) {
+		return r.removeFinalizer(ctx, kind)
+	}
 
-	if err == nil && ns.DeletionTimestamp == nil {
+	// Check if topo service is available
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
+		// Remove the finalizer if topo service is not found (deleted).
+		log.Warnf("Failed to find topo service %s in namespace %s, %s; removing kind's finalizer", topoService.Name, topoService.Namespace, err)
+		return r.removeFinalizer(ctx, kind)
+	}
+
+	// Check if the topo service may be used from the kind's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, kind.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		// Remove the finalizer without deleting the object if the topo service does not allow the namespace.
+		log.Warnf("Topo service %s does not allow namespace %s; removing kind's finalizer", topoService, kind.Namespace)
+		return r.removeFinalizer(ctx, kind)
+	}
+
+	switch kind.Status.State {
+	case "", v1beta1.StatePending, v1beta1.StateAdding, v1beta1.StateAdded:
+		// Block the deletion of the kind or cascade it to the entities and relations of the kind
+		entities, relations, err := r.getDependents(ctx, kind)
+		if err != nil {
+			log.Warnf("Failed to reconcile dependents of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		if len(entities) > 0 || len(relations) > 0 {
+			return r.reconcileDependents(ctx, kind, entities, relations)
+		}
+		if kind.Status.Dependents != nil {
+			kind.Status.Dependents = nil
+			conditions.SetDeletionBlocked(&kind.Status.ObjectStatus, kind.Generation, false, v1beta1.ReasonResolved, "")
+		}
+		conditions.SetState(&kind.Status.ObjectStatus, v1beta1.StateRemoving, kind.Generation)
+		err = r.client.Status().Update(ctx, kind)
+		if err != nil {
+			log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	case v1beta1.StateRemoving:
 		// Connect to the topology service
-		conn, err := grpc.ConnectService(r.client, kind.Namespace, topoService)
+		conn, err := r.conns.Connect(ctx, topoService)
+		if err != nil {
+			log.Warnf("Failed to reconcile deleting kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		}
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Delete, retain or orphan the topo object of the kind unless it is owned by another resource
+		if object, err := r.kindExists(ctx, kind, client); err != nil {
+			log.Warnf("Failed to reconcile deleting kind %s, %s, %s", kind.Name, kind.Namespace, err)
+			return r.syncFailed(ctx, kind, err)
+		} else if object != nil {
+			if err := owners.Check(object, kind, owners.CanClaim(&kind.Status.ObjectStatus, object, kind.Name)); err != nil {
+				log.Warnf("Not deleting kind %s from topo store, %s", kind.Name, err)
+			} else if err := r.releaseKind(ctx, kind, object, client); err != nil && !errors.IsNotFound(err) {
+				log.Warnf("Failed to reconcile deleting kind %s, %s, %s", kind.Name, kind.Namespace, err)
+				return r.syncFailed(ctx, kind, err)
+			}
+		}
+		conditions.SetState(&kind.Status.ObjectStatus, v1beta1.StateRemoved, kind.Generation)
+		err = r.client.Status().Update(ctx, kind)
 		if err != nil {
+			log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
 			return reconcile.Result{}, err
 		}
-		defer conn.Close()
+		return reconcile.Result{}, nil
+	default:
+		log.Debugf("Kind %s is already removed or never been added to the topo store.", kind.Name)
+		return r.removeFinalizer(ctx, kind)
+	}
+}
 
-		client := topo.NewTopoClient(conn)
+// getDependents returns the entities and relations of the kind
+func (r *Reconciler) getDependents(ctx context.Context, kind *v1beta1.Kind) ([]v1beta1.Entity, []v1beta1.Relation, error) {
+	kindName := client.ObjectKeyFromObject(kind)
 
-		// Delete the kind from the topology
-		if err := r.deleteKind(ctx, kind, client); err != nil {
-			return reconcile.Result{}, err
+	entityList := &v1beta1.EntityList{}
+	if err := r.client.List(ctx, entityList, client.MatchingFields{dependencies.KindNameField: kind.Name}); err != nil {
+		return nil, nil, err
+	}
+	var entities []v1beta1.Entity
+	for _, entity := range entityList.Items {
+		if !mirrors.IsMirrored(&entity) && dependencies.GetNamespacedName(entity.Namespace, entity.Spec.Kind) == kindName {
+			entities = append(entities, entity)
+		}
+	}
+
+	relationList := &v1beta1.RelationList{}
+	if err := r.client.List(ctx, relationList, client.MatchingFields{dependencies.KindNameField: kind.Name}); err != nil {
+		return nil, nil, err
+	}
+	var relations []v1beta1.Relation
+	for _, relation := range relationList.Items {
+		if !mirrors.IsMirrored(&relation) && dependencies.GetNamespacedName(relation.Namespace, relation.Spec.Kind) == kindName {
+			relations = append(relations, relation)
+		}
+	}
+	return entities, relations, nil
+}
+
+// reconcileDependents blocks the deletion of the kind while entities or relations of the kind exist. If the
+// kind's dependents policy is Cascade, the relations and then the entities of the kind are deleted first.
+func (r *Reconciler) reconcileDependents(ctx context.Context, kind *v1beta1.Kind, entities []v1beta1.Entity, relations []v1beta1.Relation) (reconcile.Result, error) {
+	var dependents []string
+	for _, relation := range relations {
+		dependents = append(dependents, fmt.Sprintf("Relation %s/%s", relation.Namespace, relation.Name))
+	}
+	for _, entity := range entities {
+		dependents = append(dependents, fmt.Sprintf("Entity %s/%s", entity.Namespace, entity.Name))
+	}
+	sort.Strings(dependents)
+	if len(dependents) > maxDependents {
+		dependents = append(dependents[:maxDependents], fmt.Sprintf("and %d more", len(dependents)-maxDependents))
+	}
+	message := fmt.Sprintf("kind is used by %d entities and %d relations", len(entities), len(relations))
+
+	if kind.Spec.DependentsPolicy == v1beta1.DependentsPolicyCascade {
+		// Delete the relations of the kind before deleting its entities
+		if len(relations) > 0 {
+			for _, relation := range relations {
+				if relation.DeletionTimestamp == nil {
+					log.Infof("Deleting relation %s/%s of kind %s", relation.Namespace, relation.Name, kind.Name)
+					if err := r.client.Delete(ctx, &relation); err != nil && !k8serrors.IsNotFound(err) {
+						log.Warnf("Failed to reconcile deleting relation %s of kind %s, %s, %s", relation.Name, kind.Name, kind.Namespace, err)
+						return reconcile.Result{}, err
+					}
+				}
+			}
+		} else {
+			for _, entity := range entities {
+				if entity.DeletionTimestamp == nil {
+					log.Infof("Deleting entity %s/%s of kind %s", entity.Namespace, entity.Name, kind.Name)
+					if err := r.client.Delete(ctx, &entity); err != nil && !k8serrors.IsNotFound(err) {
+						log.Warnf("Failed to reconcile deleting entity %s of kind %s, %s, %s", entity.Name, kind.Name, kind.Namespace, err)
+						return reconcile.Result{}, err
+					}
+				}
+			}
 		}
+		conditions.SetDeletionBlocked(&kind.Status.ObjectStatus, kind.Generation, true, v1beta1.ReasonCascadingDeletion, message)
+	} else {
+		log.Warnf("Deletion of kind %s is blocked: %s", kind.Name, message)
+		conditions.SetDeletionBlocked(&kind.Status.ObjectStatus, kind.Generation, true, v1beta1.ReasonDependentsExist, message)
 	}
 
-	// Once the kind has been deleted, remove the topology finalizer
+	kind.Status.Dependents = dependents
+	if err := r.client.Status().Update(ctx, kind); err != nil {
+		log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// removeFinalizer removes the topology finalizer from the kind
+func (r *Reconciler) removeFinalizer(ctx context.Context, kind *v1beta1.Kind) (reconcile.Result, error) {
 	k8s.RemoveFinalizer(kind, topoFinalizer)
-	if err := r.client.Update(context.TODO(), kind); err != nil {
+	if err := r.client.Update(ctx, kind); err != nil {
+		log.Warnf("Failed to reconcile removing finalizer of kind %s, %s, %s", kind.Name, kind.Namespace, err)
 		return reconcile.Result{}, err
 	}
 	return reconcile.Result{}, nil
 }
 
+// syncFailed records a failure to synchronize the kind with the topo store in its status
+func (r *Reconciler) syncFailed(ctx context.Context, kind *v1beta1.Kind, err error) (reconcile.Result, error) {
+	conditions.SetSyncFailed(&kind.Status.ObjectStatus, kind.Generation, v1beta1.ReasonSyncFailed, err)
+	if err := r.client.Status().Update(ctx, kind); err != nil {
+		log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+	}
+	return reconcile.Result{}, err
+}
+
+// ownershipConflict records in its status that the topo object of the kind is owned by another resource
+func (r *Reconciler) ownershipConflict(ctx context.Context, kind *v1beta1.Kind, err error) (reconcile.Result, error) {
+	log.Warnf("Failed to reconcile kind %s, %s, %s", kind.Name, kind.Namespace, err)
+	conditions.SetSyncFailed(&kind.Status.ObjectStatus, kind.Generation, v1beta1.ReasonOwnershipConflict, err)
+	if err := r.client.Status().Update(ctx, kind); err != nil {
+		log.Warnf("Failed to reconcile updating state of kind %s, %s, %s", kind.Name, kind.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
 func (r *Reconciler) kindExists(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) (*topo.Object, error) {
 	request := &topo.GetRequest{
 		ID: topo.ID(kind.Name),
@@ -185,6 +630,21 @@ func (r *Reconciler) kindExists(ctx context.Context, kind *v1beta1.Kind, client
 	return nil, nil
 }
 
+// kindDrift returns a description of the differences between the kind spec and the topo object
+func (r *Reconciler) kindDrift(kind *v1beta1.Kind, object *topo.Object) string {
+	var diffs []string
+	if !owners.IsOwner(object, kind) {
+		diffs = append(diffs, "owner")
+	}
+	if aspectTypes := aspects.Diff(object, kind.Spec.Aspects, kind.Status.AppliedAspects); len(aspectTypes) > 0 {
+		diffs = append(diffs, fmt.Sprintf("aspects %s", strings.Join(aspectTypes, ", ")))
+	}
+	if keys := labels.Diff(object, r.labelMapping.Get(kind), kind.Status.AppliedLabels); len(keys) > 0 {
+		diffs = append(diffs, fmt.Sprintf("labels %s", strings.Join(keys, ", ")))
+	}
+	return strings.Join(diffs, "; ")
+}
+
 func (r *Reconciler) createKind(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) error {
 	object := &topo.Object{
 		ID:   topo.ID(kind.Name),
@@ -202,6 +662,8 @@ func (r *Reconciler) createKind(ctx context.Context, kind *v1beta1.Kind, client
 			return err
 		}
 	}
+	labels.Apply(object, r.labelMapping.Get(kind), nil)
+	owners.SetOwner(object, kind)
 	log.Infof("Creating kind %+v", object)
 
 	request := &topo.CreateRequest{
@@ -228,12 +690,11 @@ func (r *Reconciler) createKind(ctx context.Context, kind *v1beta1.Kind, client
 }
 
 func (r *Reconciler) updateKind(ctx context.Context, kind *v1beta1.Kind, object *topo.Object, client topo.TopoClient) error {
-	for key, value := range kind.Spec.Aspects {
-		err := object.SetAspectBytes(key, value.Raw)
-		if err != nil {
-			return err
-		}
+	if err := aspects.Apply(object, kind.Spec.Aspects, kind.Status.AppliedAspects); err != nil {
+		return err
 	}
+	labels.Apply(object, r.labelMapping.Get(kind), kind.Status.AppliedLabels)
+	owners.SetOwner(object, kind)
 	log.Infof("Updating kind %+v", object)
 
 	request := &topo.UpdateRequest{
@@ -259,6 +720,22 @@ func (r *Reconciler) updateKind(ctx context.Context, kind *v1beta1.Kind, object
 	return nil
 }
 
+// releaseKind deletes the topo object of the kind, or leaves it in the topo store if the kind's deletion policy
+// is Retain or Orphan. Orphaned objects are released by the kind so that they can be claimed by another resource.
+func (r *Reconciler) releaseKind(ctx context.Context, kind *v1beta1.Kind, object *topo.Object, client topo.TopoClient) error {
+	switch kind.Spec.DeletionPolicy {
+	case v1beta1.DeletionPolicyRetain:
+		log.Infof("Retaining kind %s", object.ID)
+		return nil
+	case v1beta1.DeletionPolicyOrphan:
+		owners.RemoveOwner(object)
+		log.Infof("Orphaning kind %s", object.ID)
+		_, err := client.Update(ctx, &topo.UpdateRequest{Object: object})
+		return errors.FromGRPC(err)
+	}
+	return r.deleteKind(ctx, kind, client)
+}
+
 func (r *Reconciler) deleteKind(ctx context.Context, kind *v1beta1.Kind, client topo.TopoClient) error {
 	request := &topo.DeleteRequest{
 		ID: topo.ID(kind.Name),
@@ -8,37 +8,103 @@ import (
 	"context"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
 	"github.com/onosproject/onos-operator/pkg/controller/topo/entity"
+	"github.com/onosproject/onos-operator/pkg/controller/topo/fabric"
 	"github.com/onosproject/onos-operator/pkg/controller/topo/kind"
+	"github.com/onosproject/onos-operator/pkg/controller/topo/mirror"
 	"github.com/onosproject/onos-operator/pkg/controller/topo/relation"
 	"github.com/onosproject/onos-operator/pkg/controller/topo/service"
+	"github.com/onosproject/onos-operator/pkg/controller/topo/topology"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // AddControllers adds the topology controllers to the given manager
 func AddControllers(ctx context.Context, mgr manager.Manager) error {
-	if err := entity.Add(mgr); err != nil {
+	// Share a pool of connections to topo services and a watch of each topo service across the controllers
+	conns := grpc.NewConnManager(mgr.GetClient())
+	watcher := watchers.NewWatcher(conns)
+	if err := mgr.Add(watcher); err != nil {
 		return err
 	}
-	if err := kind.Add(mgr); err != nil {
+
+	if err := entity.Add(mgr, conns, watcher); err != nil {
+		return err
+	}
+	if err := kind.Add(mgr, conns, watcher); err != nil {
+		return err
+	}
+	if err := relation.Add(mgr, conns, watcher); err != nil {
+		return err
+	}
+	if err := service.Add(mgr, conns, watcher); err != nil {
 		return err
 	}
-	if err := relation.Add(mgr); err != nil {
+	if err := mirror.Add(mgr, conns); err != nil {
 		return err
 	}
-	if err := service.Add(mgr); err != nil {
+	if err := topology.Add(mgr); err != nil {
+		return err
+	}
+	if err := fabric.Add(mgr); err != nil {
 		return err
 	}
 
-	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Entity{}, "spec.kind.name", func(rawObj client.Object) []string {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Entity{}, dependencies.KindNameField, func(rawObj client.Object) []string {
 		entity := rawObj.(*v1beta1.Entity)
 		return []string{entity.Spec.Kind.Name}
 	}); err != nil {
 		return err
 	}
 
-	return mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Relation{}, "spec.kind.name", func(rawObj client.Object) []string {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Entity{}, aspects.ReferencesField, func(rawObj client.Object) []string {
+		entity := rawObj.(*v1beta1.Entity)
+		return aspects.GetReferences(entity.Spec.Aspects)
+	}); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Relation{}, dependencies.SourceNameField, func(rawObj client.Object) []string {
+		relation := rawObj.(*v1beta1.Relation)
+		return []string{relation.Spec.Source.Name}
+	}); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Relation{}, dependencies.TargetNameField, func(rawObj client.Object) []string {
+		relation := rawObj.(*v1beta1.Relation)
+		return []string{relation.Spec.Target.Name}
+	}); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1beta1.Relation{}, dependencies.KindNameField, func(rawObj client.Object) []string {
 		relation := rawObj.(*v1beta1.Relation)
 		return []string{relation.Spec.Kind.Name}
-	})
+	}); err != nil {
+		return err
+	}
+
+	for _, object := range []client.Object{&v1beta1.Entity{}, &v1beta1.Kind{}, &v1beta1.Relation{}} {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, object, mirrors.IDField, func(rawObj client.Object) []string {
+			return []string{mirrors.GetID(rawObj)}
+		}); err != nil {
+			return err
+		}
+		// Mirrored resources are not written to their topo service
+		if err := mgr.GetFieldIndexer().IndexField(ctx, object, services.ServiceField, func(rawObj client.Object) []string {
+			if mirrors.IsMirrored(rawObj) {
+				return nil
+			}
+			return []string{services.GetIndexKey(rawObj)}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
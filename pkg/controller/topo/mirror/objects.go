@@ -0,0 +1,335 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mirror
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// mirror creates or updates the resource mirroring the given topo object, returning false if the object
+// is managed by a Kind, Entity or Relation resource using the Service
+func (r *Reconciler) mirror(ctx context.Context, service *v1beta1.Service, object *topo.Object) (bool, error) {
+	managed, err := r.isManaged(ctx, service, object)
+	if err != nil || managed {
+		return false, err
+	}
+	switch object.Type {
+	case topo.Object_KIND:
+		return true, r.mirrorKind(ctx, service, object)
+	case topo.Object_ENTITY:
+		return true, r.mirrorEntity(ctx, service, object)
+	case topo.Object_RELATION:
+		return true, r.mirrorRelation(ctx, service, object)
+	}
+	return false, nil
+}
+
+// isManaged returns whether the given topo object is owned by a resource of the operator or is managed by a
+// Kind, Entity or Relation resource using the given Service
+func (r *Reconciler) isManaged(ctx context.Context, service *v1beta1.Service, object *topo.Object) (bool, error) {
+	if owners.GetOwner(object) != "" {
+		return true, nil
+	}
+
+	var resources []client.Object
+	byID := client.MatchingFields{mirrors.IDField: string(object.ID)}
+	switch object.Type {
+	case topo.Object_KIND:
+		kinds := &v1beta1.KindList{}
+		if err := r.client.List(ctx, kinds, byID); err != nil {
+			return false, err
+		}
+		for i := range kinds.Items {
+			resources = append(resources, &kinds.Items[i])
+		}
+	case topo.Object_ENTITY:
+		entities := &v1beta1.EntityList{}
+		if err := r.client.List(ctx, entities, byID); err != nil {
+			return false, err
+		}
+		for i := range entities.Items {
+			resources = append(resources, &entities.Items[i])
+		}
+	case topo.Object_RELATION:
+		relations := &v1beta1.RelationList{}
+		if err := r.client.List(ctx, relations, byID); err != nil {
+			return false, err
+		}
+		for i := range relations.Items {
+			resources = append(resources, &relations.Items[i])
+		}
+	}
+
+	for _, resource := range resources {
+		if mirrors.IsMirrored(resource) {
+			continue
+		}
+		topoService, err := services.GetObjectServiceRef(ctx, r.client, resource)
+		if err != nil {
+			return false, err
+		}
+		if topoService == client.ObjectKeyFromObject(service) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *Reconciler) mirrorKind(ctx context.Context, service *v1beta1.Service, object *topo.Object) error {
+	spec := v1beta1.KindSpec{
+		Aspects:     getAspects(object),
+		ServiceName: service.Name,
+	}
+
+	kind := &v1beta1.Kind{}
+	if err := r.client.Get(ctx, getName(service, object.ID), kind); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		kind = &v1beta1.Kind{
+			ObjectMeta: newObjectMeta(service, object.ID),
+			Spec:       spec,
+		}
+		if err := controllerutil.SetControllerReference(service, kind, r.scheme); err != nil {
+			return err
+		}
+		log.Infof("Mirroring kind %s", object.ID)
+		if err := r.client.Create(ctx, kind); err != nil {
+			return err
+		}
+	} else if !mirrors.IsMirrored(kind) {
+		log.Warnf("Unable to mirror kind %s: resource %s already exists", object.ID, kind.Name)
+		return nil
+	} else if kind.Spec.ServiceName != spec.ServiceName || !aspects.EqualAll(kind.Spec.Aspects, spec.Aspects) {
+		kind.Spec = spec
+		log.Infof("Updating mirrored kind %s", object.ID)
+		if err := r.client.Update(ctx, kind); err != nil {
+			return err
+		}
+	} else if kind.Status.State == v1beta1.StateAdded {
+		return nil
+	}
+	return r.setMirrored(ctx, kind, &kind.Status.ObjectStatus)
+}
+
+func (r *Reconciler) mirrorEntity(ctx context.Context, service *v1beta1.Service, object *topo.Object) error {
+	spec := v1beta1.EntitySpec{
+		URI:         string(object.ID),
+		Aspects:     getAspects(object),
+		ServiceName: service.Name,
+	}
+	if object.GetEntity() != nil {
+		spec.Kind.Name = mirrors.GetName(string(object.GetEntity().KindID))
+	}
+
+	entity := &v1beta1.Entity{}
+	if err := r.client.Get(ctx, getName(service, object.ID), entity); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		entity = &v1beta1.Entity{
+			ObjectMeta: newObjectMeta(service, object.ID),
+			Spec:       spec,
+		}
+		if err := controllerutil.SetControllerReference(service, entity, r.scheme); err != nil {
+			return err
+		}
+		log.Infof("Mirroring entity %s", object.ID)
+		if err := r.client.Create(ctx, entity); err != nil {
+			return err
+		}
+	} else if !mirrors.IsMirrored(entity) {
+		log.Warnf("Unable to mirror entity %s: resource %s already exists", object.ID, entity.Name)
+		return nil
+	} else if entity.Spec.URI != spec.URI || entity.Spec.Kind.Name != spec.Kind.Name ||
+		entity.Spec.ServiceName != spec.ServiceName || !aspects.EqualAll(entity.Spec.Aspects, spec.Aspects) {
+		entity.Spec = spec
+		log.Infof("Updating mirrored entity %s", object.ID)
+		if err := r.client.Update(ctx, entity); err != nil {
+			return err
+		}
+	} else if entity.Status.State == v1beta1.StateAdded {
+		return nil
+	}
+	return r.setMirrored(ctx, entity, &entity.Status.ObjectStatus)
+}
+
+func (r *Reconciler) mirrorRelation(ctx context.Context, service *v1beta1.Service, object *topo.Object) error {
+	spec := v1beta1.RelationSpec{
+		URI:         string(object.ID),
+		Aspects:     getAspects(object),
+		ServiceName: service.Name,
+	}
+	if object.GetRelation() != nil {
+		spec.Kind.Name = mirrors.GetName(string(object.GetRelation().KindID))
+		spec.Source.Name = mirrors.GetName(string(object.GetRelation().SrcEntityID))
+		spec.Source.URI = string(object.GetRelation().SrcEntityID)
+		spec.Target.Name = mirrors.GetName(string(object.GetRelation().TgtEntityID))
+		spec.Target.URI = string(object.GetRelation().TgtEntityID)
+	}
+
+	relation := &v1beta1.Relation{}
+	if err := r.client.Get(ctx, getName(service, object.ID), relation); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		relation = &v1beta1.Relation{
+			ObjectMeta: newObjectMeta(service, object.ID),
+			Spec:       spec,
+		}
+		if err := controllerutil.SetControllerReference(service, relation, r.scheme); err != nil {
+			return err
+		}
+		log.Infof("Mirroring relation %s", object.ID)
+		if err := r.client.Create(ctx, relation); err != nil {
+			return err
+		}
+	} else if !mirrors.IsMirrored(relation) {
+		log.Warnf("Unable to mirror relation %s: resource %s already exists", object.ID, relation.Name)
+		return nil
+	} else if relation.Spec.URI != spec.URI || relation.Spec.Kind.Name != spec.Kind.Name ||
+		relation.Spec.Source.Name != spec.Source.Name || relation.Spec.Source.URI != spec.Source.URI ||
+		relation.Spec.Target.Name != spec.Target.Name || relation.Spec.Target.URI != spec.Target.URI ||
+		relation.Spec.ServiceName != spec.ServiceName || !aspects.EqualAll(relation.Spec.Aspects, spec.Aspects) {
+		relation.Spec = spec
+		log.Infof("Updating mirrored relation %s", object.ID)
+		if err := r.client.Update(ctx, relation); err != nil {
+			return err
+		}
+	} else if relation.Status.State == v1beta1.StateAdded {
+		return nil
+	}
+	return r.setMirrored(ctx, relation, &relation.Status.ObjectStatus)
+}
+
+// setMirrored marks the given mirrored resource as added and synchronized with topo
+func (r *Reconciler) setMirrored(ctx context.Context, object client.Object, status *v1beta1.ObjectStatus) error {
+	conditions.SetState(status, v1beta1.StateAdded, object.GetGeneration())
+	conditions.SetDependenciesResolved(status, object.GetGeneration(), true, v1beta1.ReasonResolved, "")
+	conditions.SetSynced(status, object.GetGeneration())
+	return r.client.Status().Update(ctx, object)
+}
+
+// unmirror deletes the resource mirroring the given topo object
+func (r *Reconciler) unmirror(ctx context.Context, service *v1beta1.Service, object *topo.Object) error {
+	var resource client.Object
+	switch object.Type {
+	case topo.Object_KIND:
+		resource = &v1beta1.Kind{}
+	case topo.Object_ENTITY:
+		resource = &v1beta1.Entity{}
+	case topo.Object_RELATION:
+		resource = &v1beta1.Relation{}
+	default:
+		return nil
+	}
+	if err := r.client.Get(ctx, getName(service, object.ID), resource); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !mirrors.IsMirrored(resource) || mirrors.GetID(resource) != string(object.ID) {
+		return nil
+	}
+	log.Infof("Removing mirrored object %s", object.ID)
+	return client.IgnoreNotFound(r.client.Delete(ctx, resource))
+}
+
+// prune deletes the resources mirrored from the given Service whose topo IDs are not in the given set
+func (r *Reconciler) prune(ctx context.Context, name types.NamespacedName, ids map[string]bool) error {
+	opts := []client.ListOption{
+		client.InNamespace(name.Namespace),
+		client.MatchingLabels{
+			mirrors.MirroredLabel: "true",
+			mirrors.ServiceLabel:  name.Name,
+		},
+	}
+
+	var resources []client.Object
+	relations := &v1beta1.RelationList{}
+	if err := r.client.List(ctx, relations, opts...); err != nil {
+		return err
+	}
+	for i := range relations.Items {
+		resources = append(resources, &relations.Items[i])
+	}
+	entities := &v1beta1.EntityList{}
+	if err := r.client.List(ctx, entities, opts...); err != nil {
+		return err
+	}
+	for i := range entities.Items {
+		resources = append(resources, &entities.Items[i])
+	}
+	kinds := &v1beta1.KindList{}
+	if err := r.client.List(ctx, kinds, opts...); err != nil {
+		return err
+	}
+	for i := range kinds.Items {
+		resources = append(resources, &kinds.Items[i])
+	}
+
+	for _, resource := range resources {
+		id := mirrors.GetID(resource)
+		if ids[id] {
+			continue
+		}
+		log.Infof("Removing mirrored object %s", id)
+		if err := r.client.Delete(ctx, resource); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getName returns the namespaced name of the resource mirroring the topo object with the given ID
+func getName(service *v1beta1.Service, id topo.ID) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: service.Namespace,
+		Name:      mirrors.GetName(string(id)),
+	}
+}
+
+// newObjectMeta returns the metadata of a resource mirroring the topo object with the given ID
+func newObjectMeta(service *v1beta1.Service, id topo.ID) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: service.Namespace,
+		Name:      mirrors.GetName(string(id)),
+		Labels: map[string]string{
+			mirrors.MirroredLabel: "true",
+			mirrors.ServiceLabel:  service.Name,
+		},
+		Annotations: map[string]string{
+			mirrors.IDAnnotation: string(id),
+		},
+	}
+}
+
+// getAspects returns the JSON encoded aspects of the given topo object
+func getAspects(object *topo.Object) map[string]runtime.RawExtension {
+	if len(object.Aspects) == 0 {
+		return nil
+	}
+	values := make(map[string]runtime.RawExtension)
+	for aspectType := range object.Aspects {
+		value, err := object.GetAspectBytes(aspectType)
+		if err != nil {
+			log.Warnf("Unable to mirror aspect %s of object %s, %s", aspectType, object.ID, err)
+			continue
+		}
+		values[aspectType] = runtime.RawExtension{Raw: value}
+	}
+	return values
+}
@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mirror
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("controller", "topo", "mirror")
+
+const watchRetryInterval = 10 * time.Second
+
+// Add creates a new mirror controller and adds it to the Manager. The Manager will set fields on the
+// controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, conns *grpc.ConnManager) error {
+	r := &Reconciler{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		conns:          conns,
+		resyncInterval: k8s.GetResyncInterval(),
+		watchers:       make(map[types.NamespacedName]context.CancelFunc),
+	}
+
+	// Run the watches of mirrored onos-topo instances while the manager is running
+	if err := mgr.Add(r); err != nil {
+		return err
+	}
+
+	// Create a new controller
+	c, err := controller.New("topo-mirror-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Service, ignoring updates to the Service status
+	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for deletions of mirrored resources and requeue the Services they are mirrored from
+	mirroredHandler := handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: object.GetNamespace(),
+					Name:      object.GetLabels()[mirrors.ServiceLabel],
+				},
+			},
+		}
+	})
+	for _, object := range []client.Object{&v1beta1.Entity{}, &v1beta1.Kind{}, &v1beta1.Relation{}} {
+		err = c.Watch(&source.Kind{Type: object}, mirroredHandler, predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return false
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return false
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return mirrors.IsMirrored(e.Object)
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+var _ manager.Runnable = &Reconciler{}
+
+// Reconciler reconciles the mirrored resources of a Service object
+type Reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	config *rest.Config
+	conns  *grpc.ConnManager
+	// resyncInterval is the interval at which all mirrored resources are resynchronized
+	resyncInterval time.Duration
+	// ctx is the context of the manager, set when the reconciler is started
+	ctx      context.Context
+	watchers map[types.NamespacedName]context.CancelFunc
+	mu       sync.Mutex
+}
+
+// Start starts the watches of mirrored onos-topo instances and stops them when the manager is stopped
+func (r *Reconciler) Start(ctx context.Context) error {
+	r.mu.Lock()
+	r.ctx = ctx
+	for name := range r.watchers {
+		r.start(name)
+	}
+	r.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a Service object and mirrors the objects of its onos-topo
+// instance if mirroring is enabled in the Service.Spec
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log.Infof("Reconciling mirror of Service %s.%s", request.Namespace, request.Name)
+
+	// Fetch the Service instance
+	service := &v1beta1.Service{}
+	err := r.client.Get(ctx, request.NamespacedName, service)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Stop mirroring and remove the resources mirrored from the deleted Service
+			r.stopWatch(request.NamespacedName)
+			return reconcile.Result{}, r.prune(ctx, request.NamespacedName, nil)
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if !service.Spec.Mirror {
+		r.stopWatch(request.NamespacedName)
+		return reconcile.Result{}, r.prune(ctx, request.NamespacedName, nil)
+	}
+
+	// Watch the onos-topo instance for changes and resynchronize all mirrored resources
+	r.startWatch(request.NamespacedName)
+
+	conn, err := r.conns.Connect(ctx, client.ObjectKeyFromObject(service))
+	if err != nil {
+		log.Warnf("Failed to reconcile mirror of service %s, %s, %s", service.Name, service.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	defer conn.Release()
+
+	resp, err := topo.CreateTopoClient(conn.ClientConn).List(ctx, &topo.ListRequest{})
+	if err != nil {
+		log.Warnf("Failed to reconcile mirror of service %s, %s, %s", service.Name, service.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	ids := make(map[string]bool)
+	for i := range resp.Objects {
+		object := &resp.Objects[i]
+		mirrored, err := r.mirror(ctx, service, object)
+		if err != nil {
+			log.Warnf("Failed to reconcile mirror of object %s of service %s, %s, %s", object.ID, service.Name, service.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		if mirrored {
+			ids[string(object.ID)] = true
+		}
+	}
+	if err := r.prune(ctx, request.NamespacedName, ids); err != nil {
+		log.Warnf("Failed to reconcile mirror of service %s, %s, %s", service.Name, service.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
+// startWatch starts watching the onos-topo instance of the given Service if it is not already being watched.
+// The watch is deferred until the reconciler is started.
+func (r *Reconciler) startWatch(name types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.watchers[name]; ok {
+		return
+	}
+	r.watchers[name] = nil
+	if r.ctx != nil {
+		r.start(name)
+	}
+}
+
+// start starts the watch of the onos-topo instance of the given Service. The caller must hold the lock.
+func (r *Reconciler) start(name types.NamespacedName) {
+	log.Infof("Starting mirror of service %s", name)
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.watchers[name] = cancel
+	go r.watch(ctx, name)
+}
+
+// stopWatch stops watching the onos-topo instance of the given Service
+func (r *Reconciler) stopWatch(name types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.watchers[name]; ok {
+		log.Infof("Stopping mirror of service %s", name)
+		if cancel != nil {
+			cancel()
+		}
+		delete(r.watchers, name)
+	}
+}
+
+// watch mirrors the changes to the objects of the onos-topo instance of the given Service until the context
+// is canceled, reconnecting to onos-topo when the watch fails
+func (r *Reconciler) watch(ctx context.Context, name types.NamespacedName) {
+	for {
+		if err := r.watchObjects(ctx, name); err != nil {
+			log.Warnf("Failed to watch service %s, %s", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+func (r *Reconciler) watchObjects(ctx context.Context, name types.NamespacedName) error {
+	conn, err := r.conns.Connect(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	stream, err := topo.CreateTopoClient(conn.ClientConn).Watch(ctx, &topo.WatchRequest{Noreplay: true})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		service := &v1beta1.Service{}
+		if err := r.client.Get(ctx, name, service); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		object := &resp.Event.Object
+		if resp.Event.Type == topo.EventType_REMOVED {
+			err = r.unmirror(ctx, service, object)
+		} else {
+			_, err = r.mirror(ctx, service, object)
+		}
+		if err != nil {
+			log.Warnf("Failed to mirror object %s of service %s, %s", object.ID, name, err)
+		}
+	}
+}
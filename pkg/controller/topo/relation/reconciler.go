@@ -6,39 +6,58 @@ package relation
 
 import (
 	"context"
+	"fmt"
 	prototypes "github.com/gogo/protobuf/types"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
 	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
 	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/labels"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
+	"time"
 )
 
 var log = logging.GetLogger("controller", "topo", "relation")
 
-const topoService = "onos-topo"
 const topoFinalizer = "topo"
 
 // Add creates a new Relation controller and adds it to the Manager. The Manager will set fields on the
 // controller and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+func Add(mgr manager.Manager, conns *grpc.ConnManager, watcher *watchers.Watcher) error {
 	r := &Reconciler{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		config: mgr.GetConfig(),
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		conns:          conns,
+		watcher:        watcher,
+		resyncInterval: k8s.GetResyncInterval(),
+		labelMapping:   labels.GetMapping(),
 	}
 
 	// Create a new controller
@@ -52,6 +71,105 @@ func Add(mgr manager.Manager) error {
 	if err != nil {
 		return err
 	}
+
+	// Watch for changes to the topo objects of Relation resources made in the topo store
+	err = c.Watch(&source.Channel{Source: watcher.Relations()}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the state and aspect schemas of Kinds and requeue the relation resources that depend on them
+	err = c.Watch(&source.Kind{Type: &v1beta1.Kind{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		relationList := &v1beta1.RelationList{}
+		if err := mgr.GetClient().List(context.Background(), relationList, client.MatchingFields{dependencies.KindNameField: object.GetName()}); err != nil {
+			log.Error(err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, relation := range relationList.Items {
+			if dependencies.GetNamespacedName(relation.Namespace, relation.Spec.Kind) == client.ObjectKeyFromObject(object) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: relation.Namespace,
+						Name:      relation.Name,
+					},
+				})
+			}
+		}
+		return requests
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return dependencies.StateChanged(e.ObjectOld, e.ObjectNew) || e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the state and URIs of Entities and requeue the relation resources that depend on them
+	err = c.Watch(&source.Kind{Type: &v1beta1.Entity{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+		for _, field := range []string{dependencies.SourceNameField, dependencies.TargetNameField} {
+			relationList := &v1beta1.RelationList{}
+			if err := mgr.GetClient().List(context.Background(), relationList, client.MatchingFields{field: object.GetName()}); err != nil {
+				log.Error(err)
+				return nil
+			}
+			for _, relation := range relationList.Items {
+				if dependencies.GetNamespacedName(relation.Namespace, relation.Spec.Source.ObjectMeta) == client.ObjectKeyFromObject(object) ||
+					dependencies.GetNamespacedName(relation.Namespace, relation.Spec.Target.ObjectMeta) == client.ObjectKeyFromObject(object) {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Namespace: relation.Namespace,
+							Name:      relation.Name,
+						},
+					})
+				}
+			}
+		}
+		return requests
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return dependencies.StateChanged(e.ObjectOld, e.ObjectNew) || dependencies.URIChanged(e.ObjectOld, e.ObjectNew)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to topo services and requeue the relation resources that use them
+	serviceHandler := handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		keys, err := services.GetIndexKeys(context.Background(), mgr.GetClient(), client.ObjectKeyFromObject(object))
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, key := range keys {
+			relationList := &v1beta1.RelationList{}
+			if err := mgr.GetClient().List(context.Background(), relationList, client.MatchingFields{services.ServiceField: key}); err != nil {
+				log.Error(err)
+				return nil
+			}
+			for _, relation := range relationList.Items {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: relation.Namespace,
+						Name:      relation.Name,
+					},
+				})
+			}
+		}
+		return requests
+	})
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, serviceHandler)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, serviceHandler)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -62,6 +180,13 @@ type Reconciler struct {
 	client client.Client
 	scheme *runtime.Scheme
 	config *rest.Config
+	conns  *grpc.ConnManager
+	// watcher watches topo services for changes to the topo objects of the resources
+	watcher *watchers.Watcher
+	// resyncInterval is the interval at which added relations are verified against the topo store
+	resyncInterval time.Duration
+	// labelMapping selects the labels and annotations of relations that are copied to the labels of their topo objects
+	labelMapping labels.Mapping
 }
 
 // Reconcile reads that state of the cluster for a Relation object and makes changes based on the state read
@@ -76,13 +201,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Forget the changes watched for the topo object of the deleted relation.
 			// Return and don't requeue
+			r.watcher.ClearChanged(&v1beta1.Relation{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}})
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	// Mirrored relation resources are managed by the mirror controller
+	if mirrors.IsMirrored(relation) {
+		return reconcile.Result{}, nil
+	}
+
+	// Claim the topo objects added by a previous version of the operator, which did not record ownership
+	if owners.ClaimLegacy(relation, topoFinalizer, &relation.Status.ObjectStatus) {
+		log.Infof("Claiming topo object of relation %s, %s added by a previous version of the operator", relation.Name, relation.Namespace)
+		if err := r.client.Status().Update(ctx, relation); err != nil {
+			log.Warnf("Failed to reconcile claiming relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	if relation.DeletionTimestamp == nil {
 		return r.reconcileCreate(ctx, relation)
 	}
@@ -90,39 +232,260 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcileCreate(ctx context.Context, relation *v1beta1.Relation) (reconcile.Result, error) {
-	// Add the finalizer to the relation if necessary
-	if !k8s.HasFinalizer(relation, topoFinalizer) {
-		k8s.AddFinalizer(relation, topoFinalizer)
-		err := r.client.Update(ctx, relation)
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, relation.Namespace, relation.Spec.ServiceRef, relation.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// Check if topo service is available
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
+		// Set the state to StatePending if topo service is not found (deleted).
+		conditions.SetPending(&relation.Status.ObjectStatus, relation.Generation, v1beta1.ReasonServiceNotFound,
+			fmt.Sprintf("topo service %s not found", topoService))
+		if err := r.client.Status().Update(ctx, relation); err != nil {
+			log.Warnf("Failed to update state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		log.Warnf("Failed to find topo service %s in namespace %s, %s", topoService.Name, topoService.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// Check if the topo service may be used from the relation's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, relation.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		conditions.SetPending(&relation.Status.ObjectStatus, relation.Generation, v1beta1.ReasonServiceNotAllowed,
+			fmt.Sprintf("topo service %s does not allow namespace %s", topoService, relation.Namespace))
+		if err := r.client.Status().Update(ctx, relation); err != nil {
+			log.Warnf("Failed to update state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		log.Warnf("Topo service %s does not allow namespace %s", topoService, relation.Namespace)
+		return reconcile.Result{}, nil
+	}
+
+	// Watch the topo service for changes to the topo object of the relation
+	r.watcher.Watch(topoService)
+
+	switch relation.Status.State {
+	case "", v1beta1.StatePending:
+		conditions.SetState(&relation.Status.ObjectStatus, v1beta1.StateAdding, relation.Generation)
+		err := r.client.Status().Update(ctx, relation)
+		if err != nil {
+			log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	case v1beta1.StateAdding:
+		// Add the finalizer to the relation if necessary
+		if !k8s.HasFinalizer(relation, topoFinalizer) {
+			k8s.AddFinalizer(relation, topoFinalizer)
+			err := r.client.Update(ctx, relation)
+			if err != nil {
+				log.Warnf("Failed to reconcile adding finalizer to relation %s, %s, %s", relation.Name, relation.Namespace, err)
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, nil
+		}
+		// Wait for the objects the relation depends on to be added to topo
+		if reason, message, err := r.checkDependencies(ctx, relation); err != nil {
+			log.Warnf("Failed to reconcile dependencies of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		} else if message != "" {
+			return r.waitForDependencies(ctx, relation, reason, message)
+		}
+		// Check the aspects of the relation against the aspect schemas declared by its kind
+		if errs, err := r.validateAspects(ctx, relation); err != nil {
+			log.Warnf("Failed to reconcile aspects of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		} else if len(errs) > 0 {
+			return r.invalidAspects(ctx, relation, errs.ToAggregate())
+		}
+		// Resolve the URIs of the source and target entities of the relation
+		endpoints, err := r.resolveEndpoints(ctx, relation)
+		if err != nil {
+			log.Warnf("Failed to reconcile endpoints of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		}
+		// Connect to the topology service
+		conn, err := r.conns.Connect(ctx, topoService)
+		if err != nil {
+			log.Warnf("Failed to reconcile creating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Delete the topo object of the previously applied URI if the URI of the relation has changed
+		if err := r.migrateRelation(ctx, relation, topoService, client); err != nil {
+			log.Warnf("Failed to reconcile migrating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+		// Check if the relation exists in the topology and return it for update if so
+		if object, err := r.relationExists(ctx, relation.Spec.URI, client); err != nil {
+			return r.syncFailed(ctx, relation, err)
+		} else if object != nil {
+			if err := owners.Check(object, relation, owners.CanClaim(&relation.Status.ObjectStatus, object, getAppliedURI(relation))); err != nil {
+				return r.ownershipConflict(ctx, relation, err)
+			}
+			if err := r.updateRelation(ctx, relation, endpoints, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+				log.Warnf("Failed to reconcile creating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+				return r.syncFailed(ctx, relation, err)
+			}
+		} else if err := r.createRelation(ctx, relation, endpoints, client); err != nil && !errors.IsAlreadyExists(err) {
+			log.Warnf("Failed to reconcile creating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+		relation.Status.AppliedAspects = aspects.Types(relation.Spec.Aspects)
+		relation.Status.AppliedLabels = r.labelMapping.Get(relation)
+		relation.Status.AppliedURI = relation.Spec.URI
+		relation.Status.SourceURI = endpoints.source
+		relation.Status.TargetURI = endpoints.target
+		conditions.SetState(&relation.Status.ObjectStatus, v1beta1.StateAdded, relation.Generation)
+		conditions.SetDependenciesResolved(&relation.Status.ObjectStatus, relation.Generation, true, v1beta1.ReasonResolved, "")
+		conditions.SetSynced(&relation.Status.ObjectStatus, relation.Generation)
+		err = r.client.Status().Update(ctx, relation)
 		if err != nil {
+			log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 			return reconcile.Result{}, err
 		}
+		return reconcile.Result{}, nil
+	case v1beta1.StateAdded:
+		return r.reconcileAdded(ctx, relation, topoService)
 	}
+	return reconcile.Result{}, nil
+}
 
-	// Connect to the topology service
-	conn, err := grpc.ConnectService(r.client, relation.Namespace, topoService)
+// reconcileAdded propagates spec changes of an added relation to the topo store and periodically verifies it
+// against the topo store, re-creating or repairing the topo object if it has drifted from the Relation spec
+func (r *Reconciler) reconcileAdded(ctx context.Context, relation *v1beta1.Relation, topoService types.NamespacedName) (reconcile.Result, error) {
+	// Resolve the URIs of the source and target entities, which change when a referenced Entity changes its URI
+	endpoints, err := r.resolveEndpoints(ctx, relation)
 	if err != nil {
+		log.Warnf("Failed to reconcile endpoints of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 		return reconcile.Result{}, err
 	}
-	defer conn.Close()
 
-	client := topo.NewTopoClient(conn)
+	// If the spec, labels and endpoints have not changed since they were last applied and the topo object has not been
+	// changed in the topo store, wait for the resync interval to elapse
+	retargeted := endpoints.source != relation.Status.SourceURI || endpoints.target != relation.Status.TargetURI
+	modified := relation.Status.ObservedGeneration != relation.Generation || retargeted ||
+		!labels.Equal(r.labelMapping.Get(relation), relation.Status.AppliedLabels)
+	changed := r.watcher.ClearChanged(relation)
+	if !modified && !changed {
+		if r.resyncInterval == 0 {
+			log.Debugf("Relation %s is already added to topo store.", relation.Name)
+			return reconcile.Result{}, nil
+		}
+		if relation.Status.LastSyncTime != nil {
+			if elapsed := time.Since(relation.Status.LastSyncTime.Time); elapsed < r.resyncInterval {
+				log.Debugf("Relation %s is already added to topo store.", relation.Name)
+				return reconcile.Result{RequeueAfter: r.resyncInterval - elapsed}, nil
+			}
+		}
+	}
 
-	// Check if the relation exists in the topology and return it for update if so
-	if object, err := r.relationExists(ctx, relation, client); err != nil {
-		return reconcile.Result{}, err
-	} else if object != nil {
-		if err := r.updateRelation(ctx, relation, object, client); err != nil {
+	// Wait for the objects a modified relation depends on to be added to topo
+	if modified {
+		if reason, message, err := r.checkDependencies(ctx, relation); err != nil {
+			log.Warnf("Failed to reconcile dependencies of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 			return reconcile.Result{}, err
+		} else if message != "" {
+			return r.waitForDependencies(ctx, relation, reason, message)
+		}
+		// Check the aspects of the relation against the aspect schemas declared by its kind
+		if errs, err := r.validateAspects(ctx, relation); err != nil {
+			log.Warnf("Failed to reconcile aspects of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return reconcile.Result{}, err
+		} else if len(errs) > 0 {
+			return r.invalidAspects(ctx, relation, errs.ToAggregate())
 		}
-		return reconcile.Result{}, nil
 	}
 
-	// If the relation does not exist, create it
-	if err := r.createRelation(ctx, relation, client); err != nil {
+	// Connect to the topology service
+	conn, err := r.conns.Connect(ctx, topoService)
+	if err != nil {
+		log.Warnf("Failed to reconcile syncing relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return r.syncFailed(ctx, relation, err)
+	}
+	defer conn.Release()
+	client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+
+	// Delete the topo object of the previously applied URI if the URI of the relation has changed; the object is
+	// then created at the new URI. The topo object is also expected to be missing if a referenced entity moved.
+	migrated := relation.Status.AppliedURI != "" && relation.Status.AppliedURI != relation.Spec.URI ||
+		relation.Status.SourceURI != "" && retargeted
+	if err := r.migrateRelation(ctx, relation, topoService, client); err != nil {
+		log.Warnf("Failed to reconcile migrating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return r.syncFailed(ctx, relation, err)
+	}
+
+	var drift *v1beta1.Drift
+	if object, err := r.relationExists(ctx, relation.Spec.URI, client); err != nil {
+		log.Warnf("Failed to reconcile syncing relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return r.syncFailed(ctx, relation, err)
+	} else if object == nil {
+		if !migrated {
+			log.Warnf("Relation %s not found in topo store; re-creating", relation.Name)
+			drift = &v1beta1.Drift{
+				Type:    v1beta1.DriftMissing,
+				Message: fmt.Sprintf("relation %s was not found in topo store", relation.Spec.URI),
+			}
+		}
+		if err := r.createRelation(ctx, relation, endpoints, client); err != nil && !errors.IsAlreadyExists(err) {
+			log.Warnf("Failed to reconcile re-creating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+	} else if err := owners.Check(object, relation, owners.CanClaim(&relation.Status.ObjectStatus, object, getAppliedURI(relation))); err != nil {
+		return r.ownershipConflict(ctx, relation, err)
+	} else if message := r.relationDrift(relation, endpoints, object); message != "" {
+		if modified {
+			log.Infof("Applying generation %d of relation %s", relation.Generation, relation.Name)
+		} else {
+			log.Warnf("Relation %s has drifted from its spec (%s); repairing", relation.Name, message)
+			drift = &v1beta1.Drift{
+				Type:    v1beta1.DriftModified,
+				Message: message,
+			}
+		}
+		if err := r.updateRelation(ctx, relation, endpoints, object, client); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+			log.Warnf("Failed to reconcile updating relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+	}
+
+	previous := relation.Status.DeepCopy()
+	relation.Status.AppliedAspects = aspects.Types(relation.Spec.Aspects)
+	relation.Status.AppliedLabels = r.labelMapping.Get(relation)
+	relation.Status.AppliedURI = relation.Spec.URI
+	relation.Status.SourceURI = endpoints.source
+	relation.Status.TargetURI = endpoints.target
+	conditions.SetDependenciesResolved(&relation.Status.ObjectStatus, relation.Generation, true, v1beta1.ReasonResolved, "")
+	conditions.SetSynced(&relation.Status.ObjectStatus, relation.Generation)
+	if drift != nil {
+		drift.DetectedTime = *relation.Status.LastSyncTime
+		relation.Status.LastDrift = drift
+	}
+
+	// Only update the status if more than the sync time has changed, so resources that are in sync are not written
+	// at every resync; the previous sync time then schedules the next resync
+	synced := relation.Status.DeepCopy()
+	synced.LastSyncTime = previous.LastSyncTime
+	if previous.LastSyncTime != nil && equality.Semantic.DeepEqual(synced, previous) {
+		log.Debugf("Relation %s is in sync with topo store.", relation.Name)
+		return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+	}
+	if err := r.client.Status().Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
 }
 
 func (r *Reconciler) reconcileDelete(ctx context.Context, relation *v1beta1.Relation) (reconcile.Result, error) {
@@ -131,42 +494,209 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, relation *v1beta1.Rela
 		return reconcile.Result{}, nil
 	}
 
+	// Get topo service
+	topoService, err := services.GetServiceRef(ctx, r.client, relation.Namespace, relation.Spec.ServiceRef, relation.Spec.ServiceName)
+	if err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	// If the namespace is being deleted, a topo service in the same namespace is going away with it
 	ns := &corev1.Namespace{}
 	nsName := types.NamespacedName{
 		Name: relation.Namespace,
 	}
-	err := r.client.Get(ctx, nsName, ns)
+	err = r.client.Get(ctx, nsName, ns)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
+	if topoService.Namespace == relation.Namespace && (err != nil || ns.DeletionTimestamp != nil) {
+		return r.removeFinalizer(ctx, relation)
+	}
 
-	if err == nil && ns.DeletionTimestamp == nil {
-		// Connect to the topology service
-		conn, err := grpc.ConnectService(r.client, relation.Namespace, topoService)
+	// Check if topo service is available
+	if ok, err := services.Exists(ctx, r.client, topoService.Namespace, topoService.Name); err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		err := k8serrors.NewNotFound(corev1.Resource("services"), topoService.Name)
+		// Remove the finalizer if topo service is not found (deleted).
+		log.Warnf("Failed to find topo service %s in namespace %s, %s; removing relation's finalizer", topoService.Name, topoService.Namespace, err)
+		return r.removeFinalizer(ctx, relation)
+	}
+
+	// Check if the topo service may be used from the relation's namespace
+	if ok, err := services.IsAllowed(ctx, r.client, relation.Namespace, topoService); err != nil {
+		log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	} else if !ok {
+		// Remove the finalizer without deleting the object if the topo service does not allow the namespace.
+		log.Warnf("Topo service %s does not allow namespace %s; removing relation's finalizer", topoService, relation.Namespace)
+		return r.removeFinalizer(ctx, relation)
+	}
+
+	switch relation.Status.State {
+	case "", v1beta1.StatePending, v1beta1.StateAdding, v1beta1.StateAdded:
+		conditions.SetState(&relation.Status.ObjectStatus, v1beta1.StateRemoving, relation.Generation)
+		err := r.client.Status().Update(ctx, relation)
 		if err != nil {
+			log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 			return reconcile.Result{}, err
 		}
-		defer conn.Close()
-
-		client := topo.NewTopoClient(conn)
-
-		// Delete the relation from the topology
-		if err := r.deleteRelation(ctx, relation, client); err != nil {
+		return reconcile.Result{}, nil
+	case v1beta1.StateRemoving:
+		// Connect to the topology service
+		conn, err := r.conns.Connect(ctx, topoService)
+		if err != nil {
+			log.Warnf("Failed to reconcile deleting relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		}
+		defer conn.Release()
+		client := r.watcher.Client(topoService, topo.NewTopoClient(conn.ClientConn))
+		// Delete, retain or orphan the topo object of the relation unless it is owned by another resource
+		if object, err := r.relationExists(ctx, getAppliedURI(relation), client); err != nil {
+			log.Warnf("Failed to reconcile deleting relation %s, %s, %s", relation.Name, relation.Namespace, err)
+			return r.syncFailed(ctx, relation, err)
+		} else if object != nil {
+			if err := owners.Check(object, relation, owners.CanClaim(&relation.Status.ObjectStatus, object, getAppliedURI(relation))); err != nil {
+				log.Warnf("Not deleting relation %s from topo store, %s", relation.Name, err)
+			} else if err := r.releaseRelation(ctx, relation, object, client); err != nil && !errors.IsNotFound(err) {
+				log.Warnf("Failed to reconcile deleting relation %s, %s, %s", relation.Name, relation.Namespace, err)
+				return r.syncFailed(ctx, relation, err)
+			}
+		}
+		conditions.SetState(&relation.Status.ObjectStatus, v1beta1.StateRemoved, relation.Generation)
+		err = r.client.Status().Update(ctx, relation)
+		if err != nil {
+			log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 			return reconcile.Result{}, err
 		}
+		return reconcile.Result{}, nil
+	default:
+		log.Debugf("Relation %s is already removed or never been added to the topo store.", relation.Name)
+		return r.removeFinalizer(ctx, relation)
 	}
+}
 
-	// Once the relation has been deleted, remove the topology finalizer
+// removeFinalizer removes the topology finalizer from the relation
+func (r *Reconciler) removeFinalizer(ctx context.Context, relation *v1beta1.Relation) (reconcile.Result, error) {
 	k8s.RemoveFinalizer(relation, topoFinalizer)
-	if err := r.client.Update(context.TODO(), relation); err != nil {
+	if err := r.client.Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile removing finalizer of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// syncFailed records a failure to synchronize the relation with the topo store in its status
+func (r *Reconciler) syncFailed(ctx context.Context, relation *v1beta1.Relation, err error) (reconcile.Result, error) {
+	conditions.SetSyncFailed(&relation.Status.ObjectStatus, relation.Generation, v1beta1.ReasonSyncFailed, err)
+	if err := r.client.Status().Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+	}
+	return reconcile.Result{}, err
+}
+
+// ownershipConflict records in its status that the topo object of the relation is owned by another resource
+func (r *Reconciler) ownershipConflict(ctx context.Context, relation *v1beta1.Relation, err error) (reconcile.Result, error) {
+	log.Warnf("Failed to reconcile relation %s, %s, %s", relation.Name, relation.Namespace, err)
+	conditions.SetSyncFailed(&relation.Status.ObjectStatus, relation.Generation, v1beta1.ReasonOwnershipConflict, err)
+	if err := r.client.Status().Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
+// waitForDependencies records in its status that the relation is waiting for the objects it depends on, or for
+// the Entities it references to be created
+func (r *Reconciler) waitForDependencies(ctx context.Context, relation *v1beta1.Relation, reason, message string) (reconcile.Result, error) {
+	log.Infof("Relation %s is waiting for dependencies: %s", relation.Name, message)
+	conditions.SetDependenciesResolved(&relation.Status.ObjectStatus, relation.Generation, false, reason, message)
+	if err := r.client.Status().Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
 		return reconcile.Result{}, err
 	}
 	return reconcile.Result{}, nil
 }
 
-func (r *Reconciler) relationExists(ctx context.Context, relation *v1beta1.Relation, client topo.TopoClient) (*topo.Object, error) {
+// invalidAspects records in its status that the aspects of the relation do not conform to the aspect schemas of its kind.
+// The relation is reconciled again when it or its kind is updated.
+func (r *Reconciler) invalidAspects(ctx context.Context, relation *v1beta1.Relation, err error) (reconcile.Result, error) {
+	log.Warnf("Relation %s has invalid aspects: %s", relation.Name, err)
+	conditions.SetSyncFailed(&relation.Status.ObjectStatus, relation.Generation, v1beta1.ReasonInvalidAspects, err)
+	if err := r.client.Status().Update(ctx, relation); err != nil {
+		log.Warnf("Failed to reconcile updating state of relation %s, %s, %s", relation.Name, relation.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// validateAspects validates the aspects of the relation against the relation aspect schemas declared by its kind
+func (r *Reconciler) validateAspects(ctx context.Context, relation *v1beta1.Relation) (field.ErrorList, error) {
+	kind, err := dependencies.GetKind(ctx, r.client, relation.Namespace, relation.Spec.Kind)
+	if err != nil || kind == nil {
+		return nil, err
+	}
+	return aspects.ValidateSchemas(field.NewPath("spec", "aspects"), kind.Name, kind.Spec.RelationAspects, relation.Spec.Aspects), nil
+}
+
+// checkDependencies returns the reason and a message describing the objects the relation depends on that do not
+// exist or have not been added to topo, or an empty message if all dependencies are resolved
+func (r *Reconciler) checkDependencies(ctx context.Context, relation *v1beta1.Relation) (string, string, error) {
+	var unresolved, messages []string
+	message, err := dependencies.CheckKind(ctx, r.client, relation.Namespace, relation.Spec.Kind)
+	if err != nil {
+		return "", "", err
+	} else if message != "" {
+		messages = append(messages, message)
+	}
+	for _, endpoint := range []v1beta1.RelationEndpoint{relation.Spec.Source, relation.Spec.Target} {
+		if endpoint.Name == "" {
+			if endpoint.URI == "" {
+				unresolved = append(unresolved, "relation endpoint references no entity")
+			}
+			continue
+		}
+		name := dependencies.GetNamespacedName(relation.Namespace, endpoint.ObjectMeta)
+		entity, err := dependencies.GetEntity(ctx, r.client, relation.Namespace, endpoint.ObjectMeta)
+		if err != nil {
+			return "", "", err
+		} else if entity == nil {
+			unresolved = append(unresolved, fmt.Sprintf("entity %s not found", name))
+		} else if entity.Status.State != v1beta1.StateAdded {
+			messages = append(messages, fmt.Sprintf("entity %s not added", name))
+		}
+	}
+	if len(unresolved) > 0 {
+		return v1beta1.ReasonUnresolvedReference, strings.Join(append(unresolved, messages...), "; "), nil
+	}
+	return v1beta1.ReasonWaitingForDependencies, strings.Join(messages, "; "), nil
+}
+
+// relationEndpoints are the resolved URIs of the source and target entities of a relation
+type relationEndpoints struct {
+	source string
+	target string
+}
+
+// resolveEndpoints resolves the URIs of the source and target entities of the relation, using the URI of the
+// Entity referenced by an endpoint that does not set a URI
+func (r *Reconciler) resolveEndpoints(ctx context.Context, relation *v1beta1.Relation) (relationEndpoints, error) {
+	var endpoints relationEndpoints
+	var err error
+	if endpoints.source, err = dependencies.ResolveEndpoint(ctx, r.client, relation.Namespace, relation.Spec.Source); err != nil {
+		return endpoints, err
+	}
+	if endpoints.target, err = dependencies.ResolveEndpoint(ctx, r.client, relation.Namespace, relation.Spec.Target); err != nil {
+		return endpoints, err
+	}
+	return endpoints, nil
+}
+
+func (r *Reconciler) relationExists(ctx context.Context, uri string, client topo.TopoClient) (*topo.Object, error) {
 	request := &topo.GetRequest{
-		ID: topo.ID(relation.Spec.URI),
+		ID: topo.ID(uri),
 	}
 	resp, err := client.Get(ctx, request)
 	if err == nil {
@@ -185,15 +715,43 @@ func (r *Reconciler) relationExists(ctx context.Context, relation *v1beta1.Relat
 	return nil, nil
 }
 
-func (r *Reconciler) createRelation(ctx context.Context, relation *v1beta1.Relation, client topo.TopoClient) error {
+// relationDrift returns a description of the differences between the relation spec and the topo object
+func (r *Reconciler) relationDrift(relation *v1beta1.Relation, endpoints relationEndpoints, object *topo.Object) string {
+	var diffs []string
+	if !owners.IsOwner(object, relation) {
+		diffs = append(diffs, "owner")
+	}
+	if obj := object.GetRelation(); obj == nil {
+		diffs = append(diffs, "object is not a relation")
+	} else {
+		if obj.KindID != topo.ID(relation.Spec.Kind.Name) {
+			diffs = append(diffs, fmt.Sprintf("kind %s != %s", obj.KindID, relation.Spec.Kind.Name))
+		}
+		if obj.SrcEntityID != topo.ID(endpoints.source) {
+			diffs = append(diffs, fmt.Sprintf("source %s != %s", obj.SrcEntityID, endpoints.source))
+		}
+		if obj.TgtEntityID != topo.ID(endpoints.target) {
+			diffs = append(diffs, fmt.Sprintf("target %s != %s", obj.TgtEntityID, endpoints.target))
+		}
+	}
+	if aspectTypes := aspects.Diff(object, relation.Spec.Aspects, relation.Status.AppliedAspects); len(aspectTypes) > 0 {
+		diffs = append(diffs, fmt.Sprintf("aspects %s", strings.Join(aspectTypes, ", ")))
+	}
+	if keys := labels.Diff(object, r.labelMapping.Get(relation), relation.Status.AppliedLabels); len(keys) > 0 {
+		diffs = append(diffs, fmt.Sprintf("labels %s", strings.Join(keys, ", ")))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func (r *Reconciler) createRelation(ctx context.Context, relation *v1beta1.Relation, endpoints relationEndpoints, client topo.TopoClient) error {
 	object := &topo.Object{
 		ID:   topo.ID(relation.Spec.URI),
 		Type: topo.Object_RELATION,
 		Obj: &topo.Object_Relation{
 			Relation: &topo.Relation{
 				KindID:      topo.ID(relation.Spec.Kind.Name),
-				SrcEntityID: topo.ID(relation.Spec.Source.URI),
-				TgtEntityID: topo.ID(relation.Spec.Target.URI),
+				SrcEntityID: topo.ID(endpoints.source),
+				TgtEntityID: topo.ID(endpoints.target),
 			},
 		},
 		Aspects: make(map[string]*prototypes.Any),
@@ -204,6 +762,8 @@ func (r *Reconciler) createRelation(ctx context.Context, relation *v1beta1.Relat
 			return err
 		}
 	}
+	labels.Apply(object, r.labelMapping.Get(relation), nil)
+	owners.SetOwner(object, relation)
 	log.Infof("Creating relation %+v", object)
 
 	request := &topo.CreateRequest{
@@ -229,19 +789,26 @@ func (r *Reconciler) createRelation(ctx context.Context, relation *v1beta1.Relat
 	return nil
 }
 
-func (r *Reconciler) updateRelation(ctx context.Context, relation *v1beta1.Relation, object *topo.Object, client topo.TopoClient) error {
-	for key, value := range relation.Spec.Aspects {
-		err := object.SetAspectBytes(key, value.Raw)
-		if err != nil {
-			return err
-		}
+func (r *Reconciler) updateRelation(ctx context.Context, relation *v1beta1.Relation, endpoints relationEndpoints, object *topo.Object, client topo.TopoClient) error {
+	object.Type = topo.Object_RELATION
+	object.Obj = &topo.Object_Relation{
+		Relation: &topo.Relation{
+			KindID:      topo.ID(relation.Spec.Kind.Name),
+			SrcEntityID: topo.ID(endpoints.source),
+			TgtEntityID: topo.ID(endpoints.target),
+		},
+	}
+	if err := aspects.Apply(object, relation.Spec.Aspects, relation.Status.AppliedAspects); err != nil {
+		return err
 	}
+	labels.Apply(object, r.labelMapping.Get(relation), relation.Status.AppliedLabels)
+	owners.SetOwner(object, relation)
 	log.Infof("Updating relation %+v", object)
 
-	request := &topo.CreateRequest{
+	request := &topo.UpdateRequest{
 		Object: object,
 	}
-	_, err := client.Create(ctx, request)
+	_, err := client.Update(ctx, request)
 	if err == nil {
 		log.Infof("Relation updated: %+v", object)
 		return nil
@@ -261,9 +828,54 @@ func (r *Reconciler) updateRelation(ctx context.Context, relation *v1beta1.Relat
 	return nil
 }
 
-func (r *Reconciler) deleteRelation(ctx context.Context, relation *v1beta1.Relation, client topo.TopoClient) error {
+// releaseRelation deletes the topo object of the relation, or leaves it in the topo store if the relation's deletion policy
+// is Retain or Orphan. Orphaned objects are released by the relation so that they can be claimed by another resource.
+func (r *Reconciler) releaseRelation(ctx context.Context, relation *v1beta1.Relation, object *topo.Object, client topo.TopoClient) error {
+	switch relation.Spec.DeletionPolicy {
+	case v1beta1.DeletionPolicyRetain:
+		log.Infof("Retaining relation %s", object.ID)
+		return nil
+	case v1beta1.DeletionPolicyOrphan:
+		owners.RemoveOwner(object)
+		log.Infof("Orphaning relation %s", object.ID)
+		_, err := client.Update(ctx, &topo.UpdateRequest{Object: object})
+		return errors.FromGRPC(err)
+	}
+	return r.deleteRelation(ctx, object.ID, client)
+}
+
+// migrateRelation deletes the topo object at the previously applied URI of the relation if its URI has changed, unless
+// the object is owned by another resource. The object is created at the new URI by the caller.
+func (r *Reconciler) migrateRelation(ctx context.Context, relation *v1beta1.Relation, topoService types.NamespacedName, client topo.TopoClient) error {
+	uri := getAppliedURI(relation)
+	if uri == relation.Spec.URI {
+		return nil
+	}
+	log.Infof("Migrating relation %s from %s to %s", relation.Name, uri, relation.Spec.URI)
+	if object, err := r.relationExists(ctx, uri, client); err != nil {
+		return err
+	} else if object != nil {
+		if err := owners.Check(object, relation, owners.CanClaim(&relation.Status.ObjectStatus, object, getAppliedURI(relation))); err != nil {
+			log.Warnf("Not deleting relation %s from topo store, %s", uri, err)
+		} else if err := r.deleteRelation(ctx, object.ID, client); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAppliedURI returns the URI of the topo object of the relation, which is the previously applied URI until a change
+// of the URI in the spec of the relation has been applied
+func getAppliedURI(relation *v1beta1.Relation) string {
+	if relation.Status.AppliedURI != "" {
+		return relation.Status.AppliedURI
+	}
+	return relation.Spec.URI
+}
+
+func (r *Reconciler) deleteRelation(ctx context.Context, id topo.ID, client topo.TopoClient) error {
 	request := &topo.DeleteRequest{
-		ID: topo.ID(relation.Spec.URI),
+		ID: id,
 	}
 	log.Infof("Deleting relation %s", request.ID)
 
@@ -8,27 +8,46 @@ import (
 	"context"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sync"
+	"time"
 )
 
 var log = logging.GetLogger("controller", "topo", "service")
 
+const probeTimeout = 10 * time.Second
+
 // Add creates a new Service controller and adds it to the Manager. The Manager will set fields on the
 // controller and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+func Add(mgr manager.Manager, conns *grpc.ConnManager, watcher *watchers.Watcher) error {
 	r := &Reconciler{
-		client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		config: mgr.GetConfig(),
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		conns:          conns,
+		watcher:        watcher,
+		resyncInterval: k8s.GetResyncInterval(),
+		services:       make(map[types.NamespacedName]bool),
 	}
 
 	// Create a new controller
@@ -37,20 +56,66 @@ func Add(mgr manager.Manager) error {
 		return err
 	}
 
-	// Watch for changes to primary resource Service
-	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource Service, ignoring updates to the Service status
+	err = c.Watch(&source.Kind{Type: &v1beta1.Service{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to secondary resource Pod and requeue the owner Service
-	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
-		OwnerType: &v1beta1.Service{},
-	})
+	// Watch for changes to secondary resource Pod and requeue the Services selecting them
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		serviceList := &v1beta1.ServiceList{}
+		if err := mgr.GetClient().List(context.Background(), serviceList, &client.ListOptions{Namespace: object.GetNamespace()}); err != nil {
+			log.Error(err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, service := range serviceList.Items {
+			if service.Spec.Selector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(service.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			if selector.Matches(labels.Set(object.GetLabels())) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: service.Namespace,
+						Name:      service.Name,
+					},
+				})
+			}
+		}
+		return requests
+	}))
 	if err != nil {
 		return err
 	}
 
+	// Watch for changes to topology resources and requeue the Services managing them
+	objectHandler := handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		topoService, err := services.GetObjectServiceRef(context.Background(), mgr.GetClient(), object)
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: topoService,
+			},
+		}
+	})
+	for _, object := range []client.Object{&v1beta1.Entity{}, &v1beta1.Kind{}, &v1beta1.Relation{}} {
+		err = c.Watch(&source.Kind{Type: object}, objectHandler, predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return services.GetIndexKey(e.ObjectOld) != services.GetIndexKey(e.ObjectNew)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -58,9 +123,17 @@ var _ reconcile.Reconciler = &Reconciler{}
 
 // Reconciler reconciles a Service object
 type Reconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
-	config *rest.Config
+	client  client.Client
+	scheme  *runtime.Scheme
+	config  *rest.Config
+	conns   *grpc.ConnManager
+	watcher *watchers.Watcher
+	// resyncInterval is the interval at which the connectivity of the Service is probed
+	resyncInterval time.Duration
+	// services is the set of Services that have been reconciled; topo services without a Service resource are
+	// requeued by the resources using them but never deleted
+	services map[types.NamespacedName]bool
+	mu       sync.Mutex
 }
 
 // Reconcile reads that state of the cluster for a Service object and makes changes based on the state read
@@ -74,12 +147,123 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// If the Service was deleted, close the pooled connection to it and stop watching it unless resources
+			// still use the Kubernetes Service of the same name, in which case the watch is restarted to connect to it.
 			// Return and don't requeue
+			if r.forget(request.NamespacedName) {
+				r.conns.Disconnect(request.NamespacedName)
+				r.watcher.Unwatch(request.NamespacedName)
+				if managedObjects, err := r.countManagedObjects(ctx, request.NamespacedName); err != nil {
+					log.Warnf("Failed to reconcile managed objects of service %s, %s, %s", request.Name, request.Namespace, err)
+				} else if managedObjects > 0 {
+					r.watcher.Watch(request.NamespacedName)
+				}
+			}
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, nil
+
+	r.mu.Lock()
+	r.services[request.NamespacedName] = true
+	r.mu.Unlock()
+
+	var endpoints []string
+	targetErr := services.CheckTarget(service)
+	if targetErr == nil {
+		endpoints, err = services.GetEndpoints(ctx, r.client, service)
+		if err != nil {
+			log.Warnf("Failed to reconcile endpoints of service %s, %s, %s", service.Name, service.Namespace, err)
+			return reconcile.Result{}, err
+		}
+	}
+	managedObjects, err := r.countManagedObjects(ctx, request.NamespacedName)
+	if err != nil {
+		log.Warnf("Failed to reconcile managed objects of service %s, %s, %s", service.Name, service.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	connected := metav1.Condition{
+		Type:               v1beta1.ConditionConnected,
+		ObservedGeneration: service.Generation,
+	}
+	if targetErr != nil {
+		log.Warnf("Target of service %s, %s is not allowed, %s", service.Name, service.Namespace, targetErr)
+		connected.Status = metav1.ConditionFalse
+		connected.Reason = v1beta1.ReasonTargetNotAllowed
+		connected.Message = targetErr.Error()
+	} else if len(endpoints) == 0 {
+		connected.Status = metav1.ConditionFalse
+		connected.Reason = v1beta1.ReasonNoEndpoints
+		connected.Message = "no ready endpoints found for service"
+	} else if err := r.probe(ctx, service); err != nil {
+		log.Warnf("Failed to connect to service %s, %s, %s", service.Name, service.Namespace, err)
+		connected.Status = metav1.ConditionFalse
+		connected.Reason = v1beta1.ReasonConnectionFailed
+		connected.Message = err.Error()
+	} else {
+		connected.Status = metav1.ConditionTrue
+		connected.Reason = v1beta1.ReasonConnected
+	}
+
+	// The Service is requeued whenever a topology resource starts using it, so only update the status if it has
+	// changed or the last probe is older than the resync interval
+	status := service.Status.DeepCopy()
+	status.Endpoints = endpoints
+	status.ManagedObjects = managedObjects
+	meta.SetStatusCondition(&status.Conditions, connected)
+	if lastProbeTime := service.Status.LastProbeTime; lastProbeTime != nil && reflect.DeepEqual(status, &service.Status) {
+		if elapsed := time.Since(lastProbeTime.Time); elapsed < r.resyncInterval {
+			return reconcile.Result{RequeueAfter: r.resyncInterval - elapsed}, nil
+		}
+	}
+
+	now := metav1.Now()
+	status.LastProbeTime = &now
+	service.Status = *status
+	if err := r.client.Status().Update(ctx, service); err != nil {
+		log.Warnf("Failed to reconcile updating status of service %s, %s, %s", service.Name, service.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+}
+
+// forget returns whether the Service with the given name was reconciled before it was deleted, forgetting it
+func (r *Reconciler) forget(name types.NamespacedName) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := r.services[name]
+	delete(r.services, name)
+	return deleted
+}
+
+// probe verifies the onos-topo instance of the given Service can be reached
+func (r *Reconciler) probe(ctx context.Context, service *v1beta1.Service) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	conn, err := r.conns.Connect(ctx, client.ObjectKeyFromObject(service))
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	return grpc.WaitForReady(ctx, conn.ClientConn)
+}
+
+// countManagedObjects returns the number of topology resources using the given topo service
+func (r *Reconciler) countManagedObjects(ctx context.Context, name types.NamespacedName) (int32, error) {
+	keys, err := services.GetIndexKeys(ctx, r.client, name)
+	if err != nil {
+		return 0, err
+	}
+	var count int32
+	for _, key := range keys {
+		for _, list := range []client.ObjectList{&v1beta1.EntityList{}, &v1beta1.KindList{}, &v1beta1.RelationList{}} {
+			if err := r.client.List(ctx, list, client.MatchingFields{services.ServiceField: key}); err != nil {
+				return 0, err
+			}
+			count += int32(meta.LenList(list))
+		}
+	}
+	return count, nil
 }
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/watchers"
+	"google.golang.org/grpc/connectivity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"testing"
+)
+
+// TestFallbackService verifies that requeueing a topo service without a Service resource, which the resources using
+// it do whenever they are created or deleted, does not close the pooled connection to the Kubernetes Service
+func TestFallbackService(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "micro-onos",
+				Name:      "onos-topo",
+				Labels:    map[string]string{"app": "onos-topo"},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.96.0.12",
+				Ports:     []corev1.ServicePort{{Name: "grpc", Port: 5150}},
+			},
+		}).
+		Build()
+
+	conns := grpc.NewConnManager(c)
+	r := &Reconciler{
+		client:   c,
+		scheme:   scheme,
+		conns:    conns,
+		watcher:  watchers.NewWatcher(conns),
+		services: make(map[types.NamespacedName]bool),
+	}
+
+	ctx := context.TODO()
+	name := types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}
+	conn, err := conns.Connect(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Release()
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name}); err != nil {
+		t.Fatal(err)
+	}
+	if conn.GetState() == connectivity.Shutdown {
+		t.Error("expected connection to topo service without a Service resource to remain open")
+	}
+	pooled, err := conns.Connect(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pooled.Release()
+	if pooled.ClientConn != conn.ClientConn {
+		t.Error("expected connection to topo service without a Service resource to remain pooled")
+	}
+
+	// A Service resource of the same name that has been deleted closes the pooled connection
+	r.services[name] = true
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name}); err != nil {
+		t.Fatal(err)
+	}
+	pooled.Release()
+	if conn.GetState() != connectivity.Shutdown {
+		t.Error("expected connection to deleted Service to be closed")
+	}
+}
@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package topology
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/members"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
+)
+
+var log = logging.GetLogger("controller", "topo", "topology")
+
+// TopologyLabel is the label recording the name of the Topology a Kind, Entity or Relation is a member of
+const TopologyLabel = "topo.onosproject.org/topology"
+
+// Add creates a new Topology controller and adds it to the Manager. The Manager will set fields on the
+// controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := &Reconciler{
+		client:         mgr.GetClient(),
+		scheme:         mgr.GetScheme(),
+		config:         mgr.GetConfig(),
+		resyncInterval: k8s.GetResyncInterval(),
+	}
+
+	// Create a new controller
+	c, err := controller.New("topo-topology-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Topology, ignoring updates to the Topology status
+	err = c.Watch(&source.Kind{Type: &v1beta1.Topology{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the members of Topologies and requeue the owning Topology
+	for _, object := range []client.Object{&v1beta1.Kind{}, &v1beta1.Entity{}, &v1beta1.Relation{}} {
+		err = c.Watch(&source.Kind{Type: object}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &v1beta1.Topology{},
+			IsController: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+// Reconciler reconciles a Topology object
+type Reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	config *rest.Config
+	// resyncInterval is the interval at which Topologies with failed members are retried
+	resyncInterval time.Duration
+}
+
+// Reconcile reads that state of the cluster for a Topology object and makes changes based on the state read
+// and what is in the Topology.Spec
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log.Infof("Reconciling Topology %s.%s", request.Namespace, request.Name)
+
+	// Fetch the Topology instance
+	topology := &v1beta1.Topology{}
+	err := r.client.Get(ctx, request.NamespacedName, topology)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The members of deleted Topologies are garbage collected
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if topology.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	p := r.apply(ctx, topology)
+	if err := r.prune(ctx, topology); err != nil {
+		log.Warnf("Failed to prune Topology %s.%s, %s", topology.Name, topology.Namespace, err)
+		return reconcile.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, topology, p); err != nil {
+		log.Warnf("Failed to update status of Topology %s.%s, %s", topology.Name, topology.Namespace, err)
+		return reconcile.Result{}, err
+	}
+	if len(p.Failures) > 0 {
+		return reconcile.Result{RequeueAfter: r.resyncInterval}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// isReady returns whether the given dependency has been added to topo if it is one of the given members.
+// Dependencies outside the Topology are resolved by the member's own controller.
+func isReady(applied map[types.NamespacedName]bool, name types.NamespacedName) bool {
+	added, ok := applied[name]
+	return !ok || added
+}
+
+// apply creates or updates the members of the given Topology in dependency order: Entities are applied once
+// their Kind has been added to topo, and Relations once their Kind, source and target have been added to topo
+func (r *Reconciler) apply(ctx context.Context, topology *v1beta1.Topology) *members.Progress {
+	p := &members.Progress{
+		Members: int32(len(topology.Spec.Kinds) + len(topology.Spec.Entities) + len(topology.Spec.Relations)),
+	}
+
+	kinds := make(map[types.NamespacedName]bool)
+	for _, template := range topology.Spec.Kinds {
+		name := types.NamespacedName{Namespace: topology.Namespace, Name: template.Name}
+		kind, err := members.ApplyKind(ctx, r.client, r.scheme, topology, TopologyLabel, template)
+		if err != nil {
+			kinds[name] = p.Record("Kind", template.Name, nil, err)
+		} else {
+			kinds[name] = p.Record("Kind", template.Name, &kind.Status.ObjectStatus, nil)
+		}
+	}
+
+	entities := make(map[types.NamespacedName]bool)
+	for _, template := range topology.Spec.Entities {
+		name := types.NamespacedName{Namespace: topology.Namespace, Name: template.Name}
+		if !isReady(kinds, dependencies.GetNamespacedName(topology.Namespace, template.Spec.Kind)) {
+			entities[name] = false
+			continue
+		}
+		entity, err := members.ApplyEntity(ctx, r.client, r.scheme, topology, TopologyLabel, template)
+		if err != nil {
+			entities[name] = p.Record("Entity", template.Name, nil, err)
+		} else {
+			entities[name] = p.Record("Entity", template.Name, &entity.Status.ObjectStatus, nil)
+		}
+	}
+
+	for _, template := range topology.Spec.Relations {
+		if !isReady(kinds, dependencies.GetNamespacedName(topology.Namespace, template.Spec.Kind)) ||
+			!isReady(entities, dependencies.GetNamespacedName(topology.Namespace, template.Spec.Source.ObjectMeta)) ||
+			!isReady(entities, dependencies.GetNamespacedName(topology.Namespace, template.Spec.Target.ObjectMeta)) {
+			continue
+		}
+		relation, err := members.ApplyRelation(ctx, r.client, r.scheme, topology, TopologyLabel, template)
+		if err != nil {
+			p.Record("Relation", template.Name, nil, err)
+		} else {
+			p.Record("Relation", template.Name, &relation.Status.ObjectStatus, nil)
+		}
+	}
+	return p
+}
+
+// prune deletes the members that have been removed from the given Topology
+func (r *Reconciler) prune(ctx context.Context, topology *v1beta1.Topology) error {
+	relations := make(map[string]bool)
+	for _, template := range topology.Spec.Relations {
+		relations[template.Name] = true
+	}
+	if err := members.Prune(ctx, r.client, topology, TopologyLabel, &v1beta1.RelationList{}, relations); err != nil {
+		return err
+	}
+
+	entities := make(map[string]bool)
+	for _, template := range topology.Spec.Entities {
+		entities[template.Name] = true
+	}
+	if err := members.Prune(ctx, r.client, topology, TopologyLabel, &v1beta1.EntityList{}, entities); err != nil {
+		return err
+	}
+
+	kinds := make(map[string]bool)
+	for _, template := range topology.Spec.Kinds {
+		kinds[template.Name] = true
+	}
+	return members.Prune(ctx, r.client, topology, TopologyLabel, &v1beta1.KindList{}, kinds)
+}
+
+// updateStatus records the progress of the given Topology in its status if it changed
+func (r *Reconciler) updateStatus(ctx context.Context, topology *v1beta1.Topology, p *members.Progress) error {
+	status := topology.Status.DeepCopy()
+	status.ObservedGeneration = topology.Generation
+	status.Members = p.Members
+	status.Applied = p.Applied
+	status.Failed = int32(len(p.Failures))
+	status.Progress = p.GetProgress()
+	status.Failures = p.GetFailures()
+	meta.SetStatusCondition(&status.Conditions, p.GetReadyCondition(topology.Generation))
+
+	if equality.Semantic.DeepEqual(&topology.Status, status) {
+		return nil
+	}
+	topology.Status = *status
+	return r.client.Status().Update(ctx, topology)
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/uris"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// validateKindSpec validates the structure of the given Kind spec
+func validateKindSpec(path *field.Path, spec v1beta1.KindSpec) field.ErrorList {
+	if spec.URITemplate == "" {
+		return nil
+	}
+	if _, err := uris.Parse(spec.URITemplate); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("uriTemplate"), spec.URITemplate, err.Error())}
+	}
+	return nil
+}
+
+// validateEntitySpec validates the structure of the given Entity spec
+func validateEntitySpec(path *field.Path, spec v1beta1.EntitySpec) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateURI(path.Child("uri"), spec.URI)...)
+	if spec.Kind.Name == "" {
+		errs = append(errs, field.Required(path.Child("kind", "name"), "the kind of the entity must be specified"))
+	}
+	// Observed aspects are reported in the status of the entity, which would expose the referenced values
+	for i, aspectType := range spec.ObserveAspects {
+		if value, ok := spec.Aspects[aspectType]; ok && aspects.HasReferences(value.Raw) {
+			errs = append(errs, field.Invalid(path.Child("observeAspects").Index(i), aspectType, "aspects referencing Secrets or ConfigMaps cannot be observed"))
+		}
+	}
+	return errs
+}
+
+// validateRelationSpec validates the structure of the given Relation spec
+func validateRelationSpec(path *field.Path, spec v1beta1.RelationSpec) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateURI(path.Child("uri"), spec.URI)...)
+	if spec.Kind.Name == "" {
+		errs = append(errs, field.Required(path.Child("kind", "name"), "the kind of the relation must be specified"))
+	}
+	errs = append(errs, validateEndpoint(path.Child("source"), spec.Source)...)
+	errs = append(errs, validateEndpoint(path.Child("target"), spec.Target)...)
+	if spec.Source.URI != "" && spec.Source.URI == spec.Target.URI {
+		errs = append(errs, field.Invalid(path.Child("target", "uri"), spec.Target.URI, "the target of a relation must differ from its source"))
+	} else if spec.Source.URI == "" && spec.Target.URI == "" && spec.Source.Name != "" &&
+		spec.Source.Name == spec.Target.Name && spec.Source.Namespace == spec.Target.Namespace {
+		errs = append(errs, field.Invalid(path.Child("target", "name"), spec.Target.Name, "the target of a relation must differ from its source"))
+	}
+	return errs
+}
+
+// validateEndpoint validates the given relation endpoint references an Entity by name or a topo entity by URI
+func validateEndpoint(path *field.Path, endpoint v1beta1.RelationEndpoint) field.ErrorList {
+	if endpoint.URI != "" {
+		return validateURI(path.Child("uri"), endpoint.URI)
+	}
+	if endpoint.Name == "" {
+		return field.ErrorList{field.Required(path, "the name of an Entity or a URI must be specified")}
+	}
+	return nil
+}
+
+// validateURI validates the given topology object URI has the form <scheme>:<identifier>
+func validateURI(path *field.Path, uri string) field.ErrorList {
+	if uri == "" {
+		return field.ErrorList{field.Required(path, "a URI of the form <scheme>:<identifier> must be specified")}
+	}
+	if strings.IndexFunc(uri, unicode.IsSpace) >= 0 {
+		return field.ErrorList{field.Invalid(path, uri, "a URI must not contain whitespace")}
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || (u.Opaque == "" && u.Host == "" && u.Path == "") {
+		return field.ErrorList{field.Invalid(path, uri, "a URI must have the form <scheme>:<identifier>, e.g. p4rt:1/1/0")}
+	}
+	return nil
+}
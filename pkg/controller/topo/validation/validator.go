@@ -0,0 +1,253 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/aspects"
+	"github.com/onosproject/onos-operator/pkg/controller/util/dependencies"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var log = logging.GetLogger("controller", "topo", "validation")
+
+const validatePath = "/validate-topo"
+
+// AddValidator adds the topology validating webhook to the manager
+func AddValidator(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(validatePath, &webhook.Admission{
+		Handler: &Validator{
+			client: mgr.GetAPIReader(),
+			scheme: mgr.GetScheme(),
+		},
+	})
+	return nil
+}
+
+// Validator is a validating webhook that rejects malformed topology resources, invalid aspects, and aspects that
+// do not conform to the aspect schemas of their Kind
+type Validator struct {
+	// client reads Kinds directly from the API server, since a Kind may have been created just before
+	// the resources of the kind
+	client  client.Reader
+	scheme  *runtime.Scheme
+	decoder *admission.Decoder
+}
+
+// InjectDecoder :
+func (v *Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle :
+func (v *Validator) Handle(ctx context.Context, request admission.Request) admission.Response {
+	namespacedName := types.NamespacedName{
+		Namespace: request.Namespace,
+		Name:      request.Name,
+	}
+	log.Infof("Received admission request for %s '%s'", request.Kind.Kind, namespacedName)
+
+	var object, oldObject client.Object
+	switch request.Kind.Kind {
+	case "Entity":
+		object, oldObject = &v1beta1.Entity{}, &v1beta1.Entity{}
+	case "Kind":
+		object, oldObject = &v1beta1.Kind{}, &v1beta1.Kind{}
+	case "Relation":
+		object, oldObject = &v1beta1.Relation{}, &v1beta1.Relation{}
+	case "Topology":
+		object, oldObject = &v1beta1.Topology{}, &v1beta1.Topology{}
+	default:
+		return admission.Allowed("")
+	}
+
+	if err := v.decoder.Decode(request, object); err != nil {
+		log.Errorf("Could not decode %s '%s'", request.Kind.Kind, namespacedName, err)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if request.Operation == admissionv1.Update {
+		if err := v.decoder.DecodeRaw(request.OldObject, oldObject); err != nil {
+			log.Errorf("Could not decode %s '%s'", request.Kind.Kind, namespacedName, err)
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	} else {
+		oldObject = nil
+	}
+
+	// Mirrored resources are read-only, so only the operator may create or update them, or mark resources as
+	// mirrored or no longer mirrored
+	mirrored := mirrors.IsMirrored(object) || (oldObject != nil && mirrors.IsMirrored(oldObject))
+	if mirrored && request.UserInfo.Username != k8s.GetServiceAccountUsername() {
+		log.Infof("Rejecting %s '%s' from %s: mirrored resources are read-only", request.Kind.Kind, namespacedName, request.UserInfo.Username)
+		return admission.Denied(fmt.Sprintf("%s '%s' is mirrored from onos-topo and can only be modified by the operator", request.Kind.Kind, namespacedName))
+	}
+
+	// Mirrored resources reflect the objects in topo as they are, and resources being deleted must be
+	// allowed to remove their finalizers
+	if mirrored || object.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+
+	errs, err := v.validate(ctx, request.Namespace, object, oldObject)
+	if err != nil {
+		log.Errorf("Could not validate %s '%s'", request.Kind.Kind, namespacedName, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(errs) > 0 {
+		log.Infof("Rejecting %s '%s': %s", request.Kind.Kind, namespacedName, errs.ToAggregate())
+		gk := schema.GroupKind{Group: request.Kind.Group, Kind: request.Kind.Kind}
+		status := k8serrors.NewInvalid(gk, request.Name, errs).ErrStatus
+		return admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &status,
+			},
+		}
+	}
+	return admission.Allowed("")
+}
+
+// validate returns the validation errors of the given resource, or of the changes made to the given old resource
+func (v *Validator) validate(ctx context.Context, namespace string, object, oldObject client.Object) (field.ErrorList, error) {
+	specPath := field.NewPath("spec")
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		errs := validateEntitySpec(specPath, o.Spec)
+		errs = append(errs, validateAspects(specPath.Child("aspects"), o.Spec.Aspects, getAspects(oldObject), true)...)
+		if old, ok := oldObject.(*v1beta1.Entity); ok && old.Spec.Kind.Name == o.Spec.Kind.Name &&
+			old.Spec.Kind.Namespace == o.Spec.Kind.Namespace && aspects.EqualAll(old.Spec.Aspects, o.Spec.Aspects) {
+			return errs, nil
+		}
+		kind, err := dependencies.GetKind(ctx, v.client, namespace, o.Spec.Kind)
+		if err != nil || kind == nil {
+			return errs, err
+		}
+		return append(errs, aspects.ValidateSchemas(specPath.Child("aspects"), kind.Name, kind.Spec.EntityAspects, o.Spec.Aspects)...), nil
+	case *v1beta1.Kind:
+		errs := validateKindSpec(specPath, o.Spec)
+		errs = append(errs, validateAspects(specPath.Child("aspects"), o.Spec.Aspects, getAspects(oldObject), false)...)
+		errs = append(errs, aspects.ValidateSchemaDeclarations(specPath.Child("entityAspects"), o.Spec.EntityAspects)...)
+		errs = append(errs, aspects.ValidateSchemaDeclarations(specPath.Child("relationAspects"), o.Spec.RelationAspects)...)
+		return errs, nil
+	case *v1beta1.Relation:
+		errs := validateRelationSpec(specPath, o.Spec)
+		errs = append(errs, validateAspects(specPath.Child("aspects"), o.Spec.Aspects, getAspects(oldObject), false)...)
+		if old, ok := oldObject.(*v1beta1.Relation); ok && old.Spec.Kind.Name == o.Spec.Kind.Name &&
+			old.Spec.Kind.Namespace == o.Spec.Kind.Namespace && aspects.EqualAll(old.Spec.Aspects, o.Spec.Aspects) {
+			return errs, nil
+		}
+		kind, err := dependencies.GetKind(ctx, v.client, namespace, o.Spec.Kind)
+		if err != nil || kind == nil {
+			return errs, err
+		}
+		return append(errs, aspects.ValidateSchemas(specPath.Child("aspects"), kind.Name, kind.Spec.RelationAspects, o.Spec.Aspects)...), nil
+	case *v1beta1.Topology:
+		return v.validateTopology(ctx, namespace, specPath, o)
+	}
+	return nil, nil
+}
+
+// validateTopology validates the members of the given Topology, resolving the Kinds of its Entities and Relations
+// from the Kinds of the Topology before the Kinds in the cluster
+func (v *Validator) validateTopology(ctx context.Context, namespace string, specPath *field.Path, topology *v1beta1.Topology) (field.ErrorList, error) {
+	var errs field.ErrorList
+	kinds := make(map[types.NamespacedName]*v1beta1.KindSpec)
+	for i, template := range topology.Spec.Kinds {
+		path := specPath.Child("kinds").Index(i).Child("spec")
+		errs = append(errs, validateKindSpec(path, template.Spec)...)
+		errs = append(errs, validateAspects(path.Child("aspects"), template.Spec.Aspects, nil, false)...)
+		errs = append(errs, aspects.ValidateSchemaDeclarations(path.Child("entityAspects"), template.Spec.EntityAspects)...)
+		errs = append(errs, aspects.ValidateSchemaDeclarations(path.Child("relationAspects"), template.Spec.RelationAspects)...)
+		kinds[types.NamespacedName{Namespace: namespace, Name: template.Name}] = &topology.Spec.Kinds[i].Spec
+	}
+
+	getKind := func(ref metav1.ObjectMeta) (*v1beta1.KindSpec, error) {
+		if spec, ok := kinds[dependencies.GetNamespacedName(namespace, ref)]; ok {
+			return spec, nil
+		}
+		kind, err := dependencies.GetKind(ctx, v.client, namespace, ref)
+		if err != nil || kind == nil {
+			return nil, err
+		}
+		return &kind.Spec, nil
+	}
+
+	for i, template := range topology.Spec.Entities {
+		errs = append(errs, validateEntitySpec(specPath.Child("entities").Index(i).Child("spec"), template.Spec)...)
+		path := specPath.Child("entities").Index(i).Child("spec", "aspects")
+		errs = append(errs, validateAspects(path, template.Spec.Aspects, nil, true)...)
+		kind, err := getKind(template.Spec.Kind)
+		if err != nil {
+			return nil, err
+		} else if kind != nil {
+			errs = append(errs, aspects.ValidateSchemas(path, template.Spec.Kind.Name, kind.EntityAspects, template.Spec.Aspects)...)
+		}
+	}
+	for i, template := range topology.Spec.Relations {
+		errs = append(errs, validateRelationSpec(specPath.Child("relations").Index(i).Child("spec"), template.Spec)...)
+		path := specPath.Child("relations").Index(i).Child("spec", "aspects")
+		errs = append(errs, validateAspects(path, template.Spec.Aspects, nil, false)...)
+		kind, err := getKind(template.Spec.Kind)
+		if err != nil {
+			return nil, err
+		} else if kind != nil {
+			errs = append(errs, aspects.ValidateSchemas(path, template.Spec.Kind.Name, kind.RelationAspects, template.Spec.Aspects)...)
+		}
+	}
+	return errs, nil
+}
+
+// validateAspects validates the given aspects, skipping the aspects left unchanged by an update. Aspects referencing
+// Secrets or ConfigMaps are only allowed if allowReferences is true, and are validated once the references have
+// been resolved.
+func validateAspects(path *field.Path, values map[string]runtime.RawExtension, oldValues map[string]runtime.RawExtension, allowReferences bool) field.ErrorList {
+	var errs field.ErrorList
+	for _, aspectType := range aspects.Types(values) {
+		value := values[aspectType].Raw
+		if oldValue, ok := oldValues[aspectType]; ok && aspects.Equal(oldValue.Raw, value) {
+			continue
+		}
+		if aspects.HasReferences(value) {
+			if !allowReferences {
+				errs = append(errs, field.Forbidden(path.Key(aspectType), "only the aspects of entities may reference Secrets or ConfigMaps"))
+			} else {
+				errs = append(errs, aspects.ValidateReferences(path.Key(aspectType), aspectType, value)...)
+			}
+			continue
+		}
+		errs = append(errs, aspects.ValidateAspect(path.Key(aspectType), aspectType, value)...)
+	}
+	return errs
+}
+
+// getAspects returns the aspects of the given resource, or nil if the resource is not set
+func getAspects(object client.Object) map[string]runtime.RawExtension {
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		return o.Spec.Aspects
+	case *v1beta1.Kind:
+		return o.Spec.Aspects
+	case *v1beta1.Relation:
+		return o.Spec.Aspects
+	}
+	return nil
+}
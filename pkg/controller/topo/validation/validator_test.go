@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/mirrors"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"testing"
+)
+
+const operatorUsername = "system:serviceaccount:kube-system:onos-operator"
+
+func newKind(mirrored bool) *v1beta1.Kind {
+	kind := &v1beta1.Kind{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "Kind",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "e2-node",
+		},
+	}
+	if mirrored {
+		kind.Labels = map[string]string{mirrors.MirroredLabel: "true"}
+	}
+	return kind
+}
+
+func newRequest(t *testing.T, username string, object, oldObject *v1beta1.Kind) admission.Request {
+	request := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: v1beta1.SchemeGroupVersion.Group, Version: v1beta1.SchemeGroupVersion.Version, Kind: "Kind"},
+			Namespace: object.Namespace,
+			Name:      object.Name,
+			Operation: admissionv1.Create,
+			UserInfo:  authenticationv1.UserInfo{Username: username},
+		},
+	}
+	raw, err := json.Marshal(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Object = runtime.RawExtension{Raw: raw}
+	if oldObject != nil {
+		raw, err := json.Marshal(oldObject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Operation = admissionv1.Update
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return request
+}
+
+func TestMirroredResources(t *testing.T) {
+	t.Setenv("CONTROLLER_NAMESPACE", "kube-system")
+	t.Setenv("CONTROLLER_SERVICE_ACCOUNT", "onos-operator")
+
+	scheme := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := &Validator{
+		client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		scheme: scheme,
+	}
+	if err := validator.InjectDecoder(decoder); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		username    string
+		object      *v1beta1.Kind
+		oldObject   *v1beta1.Kind
+		allowed     bool
+	}{
+		{"user creating resource", "kubernetes-admin", newKind(false), nil, true},
+		{"user creating mirrored resource", "kubernetes-admin", newKind(true), nil, false},
+		{"user updating mirrored resource", "kubernetes-admin", newKind(true), newKind(true), false},
+		{"user marking resource as mirrored", "kubernetes-admin", newKind(true), newKind(false), false},
+		{"user unmarking mirrored resource", "kubernetes-admin", newKind(false), newKind(true), false},
+		{"operator creating mirrored resource", operatorUsername, newKind(true), nil, true},
+		{"operator updating mirrored resource", operatorUsername, newKind(true), newKind(true), true},
+		{"other service account creating mirrored resource", "system:serviceaccount:micro-onos:onos-operator", newKind(true), nil, false},
+	}
+	for _, test := range tests {
+		response := validator.Handle(context.TODO(), newRequest(t, test.username, test.object, test.oldObject))
+		if response.Allowed != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t (%v)", test.description, test.allowed, response.Allowed, response.Result)
+		}
+	}
+}
+
+func TestObserveAspects(t *testing.T) {
+	spec := v1beta1.EntitySpec{
+		URI:  "p4rt:1",
+		Kind: metav1.ObjectMeta{Name: "switch"},
+		Aspects: map[string]runtime.RawExtension{
+			"onos.topo.TLSOptions": {Raw: []byte(`{"cert":{"valueFrom":{"secretKeyRef":{"name":"switch-1-tls","key":"tls.crt"}}}}`)},
+			"onos.topo.Switch":     {Raw: []byte(`{"modelId":"tofino"}`)},
+		},
+		ObserveAspects: []string{"onos.topo.Switch", "onos.topo.MastershipState"},
+	}
+	if errs := validateEntitySpec(field.NewPath("spec"), spec); len(errs) > 0 {
+		t.Errorf("expected observed aspects to be valid: %v", errs)
+	}
+	spec.ObserveAspects = append(spec.ObserveAspects, "onos.topo.TLSOptions")
+	if errs := validateEntitySpec(field.NewPath("spec"), spec); len(errs) != 1 {
+		t.Errorf("expected observed aspect referencing a Secret to be invalid, got %v", errs)
+	}
+}
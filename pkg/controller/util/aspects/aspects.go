@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aspects
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"sort"
+)
+
+// Types returns the sorted types of the given aspects
+func Types(aspects map[string]runtime.RawExtension) []string {
+	var aspectTypes []string
+	for aspectType := range aspects {
+		aspectTypes = append(aspectTypes, aspectType)
+	}
+	sort.Strings(aspectTypes)
+	return aspectTypes
+}
+
+// Apply sets the given aspects on the object and removes the previously applied aspects that are no
+// longer present in the given aspects. Aspects that were not applied by the operator are left untouched.
+func Apply(object *topo.Object, aspects map[string]runtime.RawExtension, applied []string) error {
+	for aspectType, value := range aspects {
+		if err := object.SetAspectBytes(aspectType, value.Raw); err != nil {
+			return err
+		}
+	}
+	for _, aspectType := range Removed(aspects, applied) {
+		delete(object.Aspects, aspectType)
+	}
+	return nil
+}
+
+// Removed returns the previously applied aspect types that are no longer present in the given aspects
+func Removed(aspects map[string]runtime.RawExtension, applied []string) []string {
+	var aspectTypes []string
+	for _, aspectType := range applied {
+		if _, ok := aspects[aspectType]; !ok {
+			aspectTypes = append(aspectTypes, aspectType)
+		}
+	}
+	return aspectTypes
+}
+
+// Equal returns whether the given JSON encoded aspect values are semantically equal
+func Equal(a, b []byte) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return bytes.Equal(a, b)
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// EqualAll returns whether the given aspects have the same types and semantically equal values
+func EqualAll(a, b map[string]runtime.RawExtension) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for aspectType, aValue := range a {
+		bValue, ok := b[aspectType]
+		if !ok || !Equal(aValue.Raw, bValue.Raw) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the sorted types of the given aspects that are missing from or differ in the given object,
+// along with the previously applied aspects that have been removed from the given aspects but are still
+// present in the object
+func Diff(object *topo.Object, aspects map[string]runtime.RawExtension, applied []string) []string {
+	var aspectTypes []string
+	for aspectType, value := range aspects {
+		current, err := object.GetAspectBytes(aspectType)
+		if err != nil || !Equal(current, value.Raw) {
+			aspectTypes = append(aspectTypes, aspectType)
+		}
+	}
+	for _, aspectType := range Removed(aspects, applied) {
+		if _, ok := object.Aspects[aspectType]; ok {
+			aspectTypes = append(aspectTypes, aspectType)
+		}
+	}
+	sort.Strings(aspectTypes)
+	return aspectTypes
+}
+
+// Observe returns the JSON encoded values of the given aspect types that are present in the given object, or nil
+// if none of the aspects is present. The excluded aspect types, e.g. those resolved from Secrets, are never observed.
+func Observe(object *topo.Object, aspectTypes []string, excluded []string) map[string]runtime.RawExtension {
+	isExcluded := make(map[string]bool)
+	for _, aspectType := range excluded {
+		isExcluded[aspectType] = true
+	}
+	var values map[string]runtime.RawExtension
+	for _, aspectType := range aspectTypes {
+		if isExcluded[aspectType] {
+			continue
+		}
+		value, err := object.GetAspectBytes(aspectType)
+		if err != nil || !json.Valid(value) {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]runtime.RawExtension)
+		}
+		values[aspectType] = runtime.RawExtension{Raw: value}
+	}
+	return values
+}
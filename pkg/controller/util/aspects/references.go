@@ -0,0 +1,363 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aspects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReferencesField is the index of the Secrets and ConfigMaps referenced by the aspects of Entities
+	ReferencesField = "spec.aspects.valueFrom"
+
+	valueFromKey = "valueFrom"
+)
+
+// ValueSource references the value of an aspect, or of a field within an aspect, in a Secret or ConfigMap in the
+// namespace of the resource
+type ValueSource struct {
+	SecretKeyRef    *corev1.SecretKeySelector    `json:"secretKeyRef,omitempty"`
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// Resolved is the result of resolving the value references in the aspects of a resource
+type Resolved struct {
+	// Aspects are the aspects with the referenced values substituted for the references
+	Aspects map[string]runtime.RawExtension
+	// Referencing are the sorted types of the aspects containing references
+	Referencing []string
+	// Versions are the resource versions of the referenced Secrets and ConfigMaps keyed by reference key, or an
+	// empty string for the Secrets and ConfigMaps that do not exist
+	Versions map[string]string
+	// Unresolved are messages describing the references that could not be resolved
+	Unresolved []string
+}
+
+// GetReferenceKey returns the key under which a reference to the named Secret or ConfigMap is indexed
+func GetReferenceKey(kind string, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// GetReferences returns the keys of the Secrets and ConfigMaps referenced by the given aspects
+func GetReferences(aspects map[string]runtime.RawExtension) []string {
+	var keys []string
+	found := make(map[string]bool)
+	for _, aspectType := range Types(aspects) {
+		var v interface{}
+		if err := json.Unmarshal(aspects[aspectType].Raw, &v); err != nil {
+			continue
+		}
+		walk(v, field.NewPath(aspectType), func(path *field.Path, source *ValueSource) {
+			var key string
+			switch {
+			case source.SecretKeyRef != nil:
+				key = GetReferenceKey("Secret", source.SecretKeyRef.Name)
+			case source.ConfigMapKeyRef != nil:
+				key = GetReferenceKey("ConfigMap", source.ConfigMapKeyRef.Name)
+			default:
+				return
+			}
+			if !found[key] {
+				found[key] = true
+				keys = append(keys, key)
+			}
+		})
+	}
+	return keys
+}
+
+// HasReferences returns whether the given JSON encoded aspect value contains references to Secrets or ConfigMaps
+func HasReferences(value []byte) bool {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return false
+	}
+	found := false
+	walk(v, nil, func(*field.Path, *ValueSource) {
+		found = true
+	})
+	return found
+}
+
+// ValidateReferences validates the aspect type and the references to Secrets and ConfigMaps of the given JSON
+// encoded aspect value, whose fields can only be validated once the references have been resolved
+func ValidateReferences(path *field.Path, aspectType string, value []byte) field.ErrorList {
+	if proto.MessageType(aspectType) == nil {
+		return field.ErrorList{field.Invalid(path, aspectType, "unknown aspect type")}
+	}
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return field.ErrorList{field.Invalid(path, string(value), err.Error())}
+	}
+	var errs field.ErrorList
+	walk(v, path, func(path *field.Path, source *ValueSource) {
+		path = path.Child(valueFromKey)
+		switch {
+		case source.SecretKeyRef != nil && source.ConfigMapKeyRef != nil:
+			errs = append(errs, field.Invalid(path, "", "only one of secretKeyRef or configMapKeyRef may be specified"))
+		case source.SecretKeyRef != nil:
+			errs = append(errs, validateKeySelector(path.Child("secretKeyRef"), source.SecretKeyRef.Name, source.SecretKeyRef.Key)...)
+		case source.ConfigMapKeyRef != nil:
+			errs = append(errs, validateKeySelector(path.Child("configMapKeyRef"), source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)...)
+		default:
+			errs = append(errs, field.Required(path, "one of secretKeyRef or configMapKeyRef must be specified"))
+		}
+	})
+	return errs
+}
+
+func validateKeySelector(path *field.Path, name, key string) field.ErrorList {
+	var errs field.ErrorList
+	if name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+	if key == "" {
+		errs = append(errs, field.Required(path.Child("key"), ""))
+	}
+	return errs
+}
+
+// Resolve substitutes the values referenced by the given aspects of a resource in the given namespace for the
+// references. A reference to a whole aspect is replaced by the referenced value decoded as JSON or YAML, and a
+// reference to a field by the referenced value as a string. Missing optional values are omitted. The resource
+// versions of the referenced Secrets and ConfigMaps are recorded to detect changes to the referenced values.
+func Resolve(ctx context.Context, c client.Reader, namespace string, aspects map[string]runtime.RawExtension) (*Resolved, error) {
+	resolved := &Resolved{
+		Aspects: make(map[string]runtime.RawExtension),
+	}
+	r := &resolver{
+		ctx:       ctx,
+		client:    c,
+		namespace: namespace,
+	}
+	for _, aspectType := range Types(aspects) {
+		raw := aspects[aspectType].Raw
+		if !HasReferences(raw) {
+			resolved.Aspects[aspectType] = aspects[aspectType]
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return nil, err
+		}
+		value, ok, err := r.resolve(v, true)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Referencing = append(resolved.Referencing, aspectType)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Aspects[aspectType] = runtime.RawExtension{Raw: data}
+	}
+	resolved.Unresolved = r.unresolved
+	resolved.Versions = r.versions
+	return resolved, nil
+}
+
+// EqualVersions returns whether the given resource versions of referenced Secrets and ConfigMaps are equal
+func EqualVersions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, aVersion := range a {
+		if bVersion, ok := b[key]; !ok || aVersion != bVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// resolver resolves the references in aspect values
+type resolver struct {
+	ctx        context.Context
+	client     client.Reader
+	namespace  string
+	unresolved []string
+	versions   map[string]string
+}
+
+// resolve returns the given value with the referenced values substituted for the references, and whether the value
+// is present. A referenced value is decoded if the reference is the whole aspect.
+func (r *resolver) resolve(v interface{}, decode bool) (interface{}, bool, error) {
+	if source, ok := getValueSource(v); ok {
+		data, ok, err := r.getValue(source)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		if !decode {
+			return string(data), true, nil
+		}
+		data, err = yaml.ToJSON(data)
+		if err != nil {
+			r.unresolved = append(r.unresolved, fmt.Sprintf("invalid aspect value in %s: %s", source, err))
+			return nil, false, nil
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			r.unresolved = append(r.unresolved, fmt.Sprintf("invalid aspect value in %s: %s", source, err))
+			return nil, false, nil
+		}
+		return value, true, nil
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{})
+		for key, item := range value {
+			resolved, ok, err := r.resolve(item, false)
+			if err != nil {
+				return nil, false, err
+			} else if ok {
+				object[key] = resolved
+			}
+		}
+		return object, true, nil
+	case []interface{}:
+		list := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			resolved, ok, err := r.resolve(item, false)
+			if err != nil {
+				return nil, false, err
+			} else if ok {
+				list = append(list, resolved)
+			}
+		}
+		return list, true, nil
+	}
+	return v, true, nil
+}
+
+// getValue returns the value referenced by the given source, and whether the value exists
+func (r *resolver) getValue(source *ValueSource) ([]byte, bool, error) {
+	switch {
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		name := types.NamespacedName{Namespace: r.namespace, Name: ref.Name}
+		secret := &corev1.Secret{}
+		if err := r.client.Get(r.ctx, name, secret); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, false, err
+			}
+			r.setVersion(GetReferenceKey("Secret", ref.Name), "")
+			r.missing(ref.Optional, fmt.Sprintf("secret %s not found", name))
+			return nil, false, nil
+		}
+		r.setVersion(GetReferenceKey("Secret", ref.Name), secret.ResourceVersion)
+		if data, ok := secret.Data[ref.Key]; ok {
+			return data, true, nil
+		}
+		r.missing(ref.Optional, fmt.Sprintf("key %s not found in secret %s", ref.Key, name))
+		return nil, false, nil
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		name := types.NamespacedName{Namespace: r.namespace, Name: ref.Name}
+		configMap := &corev1.ConfigMap{}
+		if err := r.client.Get(r.ctx, name, configMap); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, false, err
+			}
+			r.setVersion(GetReferenceKey("ConfigMap", ref.Name), "")
+			r.missing(ref.Optional, fmt.Sprintf("configmap %s not found", name))
+			return nil, false, nil
+		}
+		r.setVersion(GetReferenceKey("ConfigMap", ref.Name), configMap.ResourceVersion)
+		if data, ok := configMap.Data[ref.Key]; ok {
+			return []byte(data), true, nil
+		}
+		if data, ok := configMap.BinaryData[ref.Key]; ok {
+			return data, true, nil
+		}
+		r.missing(ref.Optional, fmt.Sprintf("key %s not found in configmap %s", ref.Key, name))
+		return nil, false, nil
+	}
+	return nil, false, nil
+}
+
+// setVersion records the resource version of the referenced Secret or ConfigMap with the given reference key
+func (r *resolver) setVersion(key string, version string) {
+	if r.versions == nil {
+		r.versions = make(map[string]string)
+	}
+	r.versions[key] = version
+}
+
+// missing records a missing referenced value as unresolved unless the reference is optional
+func (r *resolver) missing(optional *bool, message string) {
+	if optional == nil || !*optional {
+		r.unresolved = append(r.unresolved, message)
+	}
+}
+
+// String returns a description of the Secret or ConfigMap key referenced by the value source
+func (s *ValueSource) String() string {
+	switch {
+	case s.SecretKeyRef != nil:
+		return fmt.Sprintf("key %s of secret %s", s.SecretKeyRef.Key, s.SecretKeyRef.Name)
+	case s.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("key %s of configmap %s", s.ConfigMapKeyRef.Key, s.ConfigMapKeyRef.Name)
+	}
+	return ""
+}
+
+// walk calls the given function with the path and value source of each reference within the given value
+func walk(v interface{}, path *field.Path, fn func(*field.Path, *ValueSource)) {
+	if source, ok := getValueSource(v); ok {
+		fn(path, source)
+		return
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			walk(value[key], path.Child(key), fn)
+		}
+	case []interface{}:
+		for i, item := range value {
+			walk(item, path.Index(i), fn)
+		}
+	}
+}
+
+// getValueSource returns the value source of the given value if the value is a reference, i.e. an object with a
+// single valueFrom field
+func getValueSource(v interface{}) (*ValueSource, bool) {
+	object, ok := v.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return nil, false
+	}
+	valueFrom, ok := object[valueFromKey].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(valueFrom)
+	if err != nil {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	source := &ValueSource{}
+	if err := decoder.Decode(source); err != nil {
+		return nil, false
+	}
+	return source, true
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aspects
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+const p4rtServerInfo = "onos.topo.P4RTServerInfo"
+
+// TestResolve verifies the substitution of the values of Secrets and ConfigMaps for the references in aspects
+func TestResolve(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "micro-onos",
+					Name:      "switch-1",
+				},
+				Data: map[string][]byte{
+					"p4rt.yaml": []byte("controlEndpoint:\n  address: 10.0.0.1\n  port: 9559\n"),
+					"p4rt.json": []byte(`{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`),
+					"invalid":   []byte("controlEndpoint: [\n"),
+				},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "micro-onos",
+					Name:      "endpoints",
+				},
+				Data: map[string]string{
+					"address": "10.0.0.1",
+					"port":    "9559",
+				},
+			},
+		).
+		Build()
+
+	tests := []struct {
+		name       string
+		aspect     string
+		expected   string
+		omitted    bool
+		unresolved int
+		versions   []string
+	}{
+		{
+			name:     "no references",
+			aspect:   `{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`,
+		},
+		{
+			name:     "whole aspect YAML",
+			aspect:   `{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"p4rt.yaml"}}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`,
+			versions: []string{"Secret/switch-1"},
+		},
+		{
+			name:     "whole aspect JSON",
+			aspect:   `{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"p4rt.json"}}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`,
+			versions: []string{"Secret/switch-1"},
+		},
+		{
+			name:       "whole aspect invalid YAML",
+			aspect:     `{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"invalid"}}}`,
+			omitted:    true,
+			unresolved: 1,
+			versions:   []string{"Secret/switch-1"},
+		},
+		{
+			// Referenced field values are substituted as strings, even if they look like numbers
+			name:     "fields",
+			aspect:   `{"controlEndpoint":{"address":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"address"}}},"port":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"port"}}}}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1","port":"9559"}}`,
+			versions: []string{"ConfigMap/endpoints"},
+		},
+		{
+			name:     "field referencing a whole secret value",
+			aspect:   `{"controlEndpoint":{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"p4rt.json"}}}}`,
+			expected: `{"controlEndpoint":"{\"controlEndpoint\":{\"address\":\"10.0.0.1\",\"port\":9559}}"}`,
+			versions: []string{"Secret/switch-1"},
+		},
+		{
+			name:     "optional missing secret",
+			aspect:   `{"controlEndpoint":{"address":"10.0.0.1","port":{"valueFrom":{"secretKeyRef":{"name":"switch-2","key":"port","optional":true}}}}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1"}}`,
+			versions: []string{"Secret/switch-2"},
+		},
+		{
+			name:     "optional missing key",
+			aspect:   `{"controlEndpoint":{"address":"10.0.0.1","port":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"grpc-port","optional":true}}}}}`,
+			expected: `{"controlEndpoint":{"address":"10.0.0.1"}}`,
+			versions: []string{"ConfigMap/endpoints"},
+		},
+		{
+			name:     "optional missing aspect",
+			aspect:   `{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"p4rt.toml","optional":true}}}`,
+			omitted:  true,
+			versions: []string{"Secret/switch-1"},
+		},
+		{
+			name:       "missing key",
+			aspect:     `{"controlEndpoint":{"address":"10.0.0.1","port":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"grpc-port"}}}}}`,
+			expected:   `{"controlEndpoint":{"address":"10.0.0.1"}}`,
+			unresolved: 1,
+			versions:   []string{"ConfigMap/endpoints"},
+		},
+		{
+			name:       "missing configmap",
+			aspect:     `{"valueFrom":{"configMapKeyRef":{"name":"switches","key":"switch-1"}}}`,
+			omitted:    true,
+			unresolved: 1,
+			versions:   []string{"ConfigMap/switches"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aspects := map[string]runtime.RawExtension{
+				p4rtServerInfo: {Raw: []byte(test.aspect)},
+			}
+			resolved, err := Resolve(context.TODO(), c, "micro-onos", aspects)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aspect, ok := resolved.Aspects[p4rtServerInfo]
+			if test.omitted && ok {
+				t.Errorf("expected aspect to be omitted, got %s", aspect.Raw)
+			} else if !test.omitted && string(aspect.Raw) != test.expected {
+				t.Errorf("expected aspect %s, got %s", test.expected, aspect.Raw)
+			}
+
+			if len(resolved.Unresolved) != test.unresolved {
+				t.Errorf("expected %d unresolved references, got %v", test.unresolved, resolved.Unresolved)
+			}
+
+			referencing := len(test.versions) > 0
+			if referencing && (len(resolved.Referencing) != 1 || resolved.Referencing[0] != p4rtServerInfo) {
+				t.Errorf("expected aspect to be referencing, got %v", resolved.Referencing)
+			} else if !referencing && len(resolved.Referencing) > 0 {
+				t.Errorf("expected no referencing aspects, got %v", resolved.Referencing)
+			}
+
+			if len(resolved.Versions) != len(test.versions) {
+				t.Errorf("expected versions of %v, got %v", test.versions, resolved.Versions)
+			}
+			for _, key := range test.versions {
+				if _, ok := resolved.Versions[key]; !ok {
+					t.Errorf("expected version of %s, got %v", key, resolved.Versions)
+				}
+			}
+		})
+	}
+}
+
+// TestGetReferences verifies the keys of the Secrets and ConfigMaps referenced by aspects
+func TestGetReferences(t *testing.T) {
+	aspects := map[string]runtime.RawExtension{
+		p4rtServerInfo:         {Raw: []byte(`{"controlEndpoint":{"address":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"address"}}},"port":{"valueFrom":{"configMapKeyRef":{"name":"endpoints","key":"port"}}}}}`)},
+		"onos.topo.TLSOptions": {Raw: []byte(`{"valueFrom":{"secretKeyRef":{"name":"switch-1","key":"tls"}}}`)},
+		"onos.topo.Location":   {Raw: []byte(`{"lat":1,"lng":2}`)},
+	}
+	expected := []string{"ConfigMap/endpoints", "Secret/switch-1"}
+	keys := GetReferences(aspects)
+	if len(keys) != len(expected) {
+		t.Fatalf("expected references %v, got %v", expected, keys)
+	}
+	for i := range keys {
+		if keys[i] != expected[i] {
+			t.Errorf("expected references %v, got %v", expected, keys)
+		}
+	}
+}
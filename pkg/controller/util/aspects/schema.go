@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aspects
+
+import (
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// ValidateSchemas validates the given aspects of an Entity or Relation against the aspect schemas declared by
+// its Kind, returning an error for each missing required aspect and for each value that does not conform to the
+// schema of its aspect
+func ValidateSchemas(path *field.Path, kind string, schemas []v1beta1.AspectSchema, values map[string]runtime.RawExtension) field.ErrorList {
+	var errs field.ErrorList
+	for _, schema := range schemas {
+		aspectPath := path.Key(schema.Type)
+		value, ok := values[schema.Type]
+		if !ok {
+			if schema.Required {
+				errs = append(errs, field.Required(aspectPath, fmt.Sprintf("required by kind %s", kind)))
+			}
+			continue
+		}
+		// Values referencing Secrets or ConfigMaps are validated once the references have been resolved
+		if schema.Schema == nil || HasReferences(value.Raw) {
+			continue
+		}
+		validator, err := newSchemaValidator(schema.Schema)
+		if err != nil {
+			errs = append(errs, field.InternalError(aspectPath, fmt.Errorf("invalid schema declared by kind %s: %s", kind, err)))
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(value.Raw, &v); err != nil {
+			errs = append(errs, field.Invalid(aspectPath, string(value.Raw), err.Error()))
+			continue
+		}
+		errs = append(errs, validation.ValidateCustomResource(aspectPath, v, validator)...)
+	}
+	return errs
+}
+
+// ValidateSchemaDeclarations validates the aspect schemas declared by a Kind
+func ValidateSchemaDeclarations(path *field.Path, schemas []v1beta1.AspectSchema) field.ErrorList {
+	var errs field.ErrorList
+	aspectTypes := make(map[string]bool)
+	for i, schema := range schemas {
+		schemaPath := path.Index(i)
+		switch {
+		case schema.Type == "":
+			errs = append(errs, field.Required(schemaPath.Child("type"), "aspect type must be specified"))
+		case proto.MessageType(schema.Type) == nil:
+			errs = append(errs, field.Invalid(schemaPath.Child("type"), schema.Type, "unknown aspect type"))
+		case aspectTypes[schema.Type]:
+			errs = append(errs, field.Duplicate(schemaPath.Child("type"), schema.Type))
+		}
+		aspectTypes[schema.Type] = true
+		if schema.Schema != nil {
+			if _, err := newSchemaValidator(schema.Schema); err != nil {
+				errs = append(errs, field.Invalid(schemaPath.Child("schema"), "", err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// newSchemaValidator returns a validator for the given aspect schema
+func newSchemaValidator(schema *apiextensionsv1.JSONSchemaProps) (*validate.SchemaValidator, error) {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internal, nil); err != nil {
+		return nil, err
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{
+		OpenAPIV3Schema: internal,
+	})
+	return validator, err
+}
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aspects
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// wellKnownType is implemented by the protobuf well-known types, which have a special JSON encoding
+type wellKnownType interface {
+	XXX_WellKnownType() string
+}
+
+// Validate validates the given aspects against the protobuf types registered under their aspect types,
+// returning an error for each unknown aspect type, unknown field or invalid value at its JSON path under
+// the given path
+func Validate(path *field.Path, aspects map[string]runtime.RawExtension) field.ErrorList {
+	var errs field.ErrorList
+	for _, aspectType := range Types(aspects) {
+		errs = append(errs, ValidateAspect(path.Key(aspectType), aspectType, aspects[aspectType].Raw)...)
+	}
+	return errs
+}
+
+// ValidateAspect validates the given JSON encoded aspect value against the protobuf type of the given aspect type
+func ValidateAspect(path *field.Path, aspectType string, value []byte) field.ErrorList {
+	messageType := proto.MessageType(aspectType)
+	if messageType == nil {
+		return field.ErrorList{field.Invalid(path, aspectType, "unknown aspect type")}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return field.ErrorList{field.Invalid(path, string(value), err.Error())}
+	}
+	if errs := validateMessage(path, messageType, v); len(errs) > 0 {
+		return errs
+	}
+
+	// Ensure the aspect can be decoded the same way it is by topo clients
+	message := reflect.New(messageType.Elem()).Interface().(proto.Message)
+	if err := jsonpb.Unmarshal(bytes.NewReader(value), message); err != nil {
+		return field.ErrorList{field.Invalid(path, string(value), err.Error())}
+	}
+	return nil
+}
+
+// validateMessage validates the given JSON value against the given protobuf message type
+func validateMessage(path *field.Path, messageType reflect.Type, v interface{}) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+	if messageType.Kind() == reflect.Ptr {
+		messageType = messageType.Elem()
+	}
+	if messageType == timeType {
+		return validateString(path, v)
+	}
+	if _, ok := reflect.New(messageType).Interface().(wellKnownType); ok {
+		// Well-known types are validated when the aspect is decoded
+		return nil
+	}
+
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return field.ErrorList{field.TypeInvalid(path, v, "must be an object")}
+	}
+
+	fields := getFields(messageType)
+	var errs field.ErrorList
+	for _, name := range sortedKeys(object) {
+		f, ok := fields[name]
+		if !ok {
+			message := reflect.New(messageType).Interface().(proto.Message)
+			errs = append(errs, field.Forbidden(path.Child(name), fmt.Sprintf("unknown field of %s", proto.MessageName(message))))
+			continue
+		}
+		errs = append(errs, validateField(path.Child(name), f.fieldType, f.props, object[name])...)
+	}
+	return errs
+}
+
+// messageField is a field of a protobuf message
+type messageField struct {
+	fieldType reflect.Type
+	props     *proto.Properties
+}
+
+// getFields returns the fields of the given protobuf message type by their original and JSON names
+func getFields(messageType reflect.Type) map[string]messageField {
+	props := proto.GetProperties(messageType)
+	fields := make(map[string]messageField)
+	for i, prop := range props.Prop {
+		structField := messageType.Field(i)
+		if strings.HasPrefix(structField.Name, "XXX_") || structField.Tag.Get("protobuf_oneof") != "" {
+			continue
+		}
+		f := messageField{fieldType: structField.Type, props: prop}
+		fields[prop.OrigName] = f
+		if prop.JSONName != "" {
+			fields[prop.JSONName] = f
+		}
+	}
+	for name, oneof := range props.OneofTypes {
+		f := messageField{fieldType: oneof.Type.Elem().Field(0).Type, props: oneof.Prop}
+		fields[name] = f
+		if oneof.Prop.JSONName != "" {
+			fields[oneof.Prop.JSONName] = f
+		}
+	}
+	return fields
+}
+
+// validateField validates the given JSON value against the given protobuf field
+func validateField(path *field.Path, fieldType reflect.Type, props *proto.Properties, v interface{}) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+	switch {
+	case fieldType.Kind() == reflect.Map:
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be an object")}
+		}
+		var errs field.ErrorList
+		for _, key := range sortedKeys(object) {
+			errs = append(errs, validateValue(path.Key(key), fieldType.Elem(), props.MapValProp, object[key])...)
+		}
+		return errs
+	case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8:
+		values, ok := v.([]interface{})
+		if !ok {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be an array")}
+		}
+		var errs field.ErrorList
+		for i, value := range values {
+			errs = append(errs, validateValue(path.Index(i), fieldType.Elem(), props, value)...)
+		}
+		return errs
+	default:
+		return validateValue(path, fieldType, props, v)
+	}
+}
+
+// validateValue validates the given JSON value against the given singular protobuf field type
+func validateValue(path *field.Path, valueType reflect.Type, props *proto.Properties, v interface{}) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if props != nil && props.CustomType != "" {
+		// Custom types are validated when the aspect is decoded
+		return nil
+	}
+	if props != nil && props.Enum != "" {
+		return validateEnum(path, props.Enum, v)
+	}
+	if valueType == durationType {
+		return validateString(path, v)
+	}
+
+	switch valueType.Kind() {
+	case reflect.Struct:
+		return validateMessage(path, valueType, v)
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be a boolean")}
+		}
+	case reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(getNumber(v), 10, 64); err != nil {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be an integer")}
+		}
+	case reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(getNumber(v), 10, 64); err != nil {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be an unsigned integer")}
+		}
+	case reflect.Float32, reflect.Float64:
+		number := getNumber(v)
+		if _, err := strconv.ParseFloat(number, 64); err != nil && number != "NaN" && number != "Infinity" && number != "-Infinity" {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be a number")}
+		}
+	case reflect.String, reflect.Slice:
+		return validateString(path, v)
+	}
+	return nil
+}
+
+// validateEnum validates the given JSON value is the name or number of a value of the given enum type
+func validateEnum(path *field.Path, enumType string, v interface{}) field.ErrorList {
+	values := proto.EnumValueMap(enumType)
+	switch value := v.(type) {
+	case json.Number:
+		if _, err := strconv.ParseInt(value.String(), 10, 32); err != nil {
+			return field.ErrorList{field.TypeInvalid(path, v, "must be an enum value")}
+		}
+	case string:
+		if _, ok := values[value]; !ok {
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return field.ErrorList{field.NotSupported(path, value, names)}
+		}
+	default:
+		return field.ErrorList{field.TypeInvalid(path, v, "must be an enum value")}
+	}
+	return nil
+}
+
+func validateString(path *field.Path, v interface{}) field.ErrorList {
+	if _, ok := v.(string); !ok {
+		return field.ErrorList{field.TypeInvalid(path, v, "must be a string")}
+	}
+	return nil
+}
+
+// getNumber returns the string representation of a JSON number, which may also be encoded as a JSON string
+func getNumber(v interface{}) string {
+	switch value := v.(type) {
+	case json.Number:
+		return value.String()
+	case string:
+		return value
+	}
+	return ""
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
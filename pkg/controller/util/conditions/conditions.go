@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package conditions
+
+import (
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetState sets the state of a topology resource and updates its Ready condition
+func SetState(status *v1beta1.ObjectStatus, state v1beta1.State, generation int64) {
+	status.State = state
+	setReady(status, generation)
+}
+
+// SetPending marks a topology resource as waiting for its topo service
+func SetPending(status *v1beta1.ObjectStatus, generation int64, reason string, message string) {
+	status.State = v1beta1.StatePending
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1beta1.ConditionSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	setReady(status, generation)
+}
+
+// SetSynced records the successful synchronization of the given generation of a topology resource with topo
+func SetSynced(status *v1beta1.ObjectStatus, generation int64) {
+	now := metav1.Now()
+	status.ObservedGeneration = generation
+	status.LastSyncTime = &now
+	status.LastError = ""
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1beta1.ConditionSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             v1beta1.ReasonSynced,
+	})
+	setReady(status, generation)
+}
+
+// SetSyncFailed records a failure to synchronize a topology resource with topo
+func SetSyncFailed(status *v1beta1.ObjectStatus, generation int64, reason string, err error) {
+	status.LastError = err.Error()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1beta1.ConditionSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            err.Error(),
+	})
+	setReady(status, generation)
+}
+
+// SetDependenciesResolved sets the DependenciesResolved condition of a topology resource
+func SetDependenciesResolved(status *v1beta1.ObjectStatus, generation int64, resolved bool, reason string, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if resolved {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1beta1.ConditionDependenciesResolved,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	setReady(status, generation)
+}
+
+// SetDeletionBlocked sets the DeletionBlocked condition of a topology resource
+func SetDeletionBlocked(status *v1beta1.ObjectStatus, generation int64, blocked bool, reason string, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if blocked {
+		conditionStatus = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               v1beta1.ConditionDeletionBlocked,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setReady derives the Ready condition from the state, the observed generation and the Synced and
+// DependenciesResolved conditions
+func setReady(status *v1beta1.ObjectStatus, generation int64) {
+	condition := metav1.Condition{
+		Type:               v1beta1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             string(status.State),
+	}
+	resolved := meta.FindStatusCondition(status.Conditions, v1beta1.ConditionDependenciesResolved)
+	synced := meta.FindStatusCondition(status.Conditions, v1beta1.ConditionSynced)
+	switch {
+	case (status.State == v1beta1.StateAdding || status.State == v1beta1.StateAdded) &&
+		resolved != nil && resolved.Status == metav1.ConditionFalse:
+		condition.Reason = resolved.Reason
+		condition.Message = resolved.Message
+	case status.State != v1beta1.StateAdded:
+		if status.State == "" {
+			condition.Reason = string(v1beta1.StatePending)
+		}
+	case synced != nil && synced.Status == metav1.ConditionFalse:
+		condition.Reason = synced.Reason
+		condition.Message = synced.Message
+	case status.ObservedGeneration != generation:
+		condition.Reason = string(v1beta1.StateAdding)
+		condition.Message = "the latest generation has not been applied"
+	default:
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// KindNameField is the index of the name of the Kind referenced by Entities and Relations
+	KindNameField = "spec.kind.name"
+	// SourceNameField is the index of the name of the source Entity referenced by Relations
+	SourceNameField = "spec.source.name"
+	// TargetNameField is the index of the name of the target Entity referenced by Relations
+	TargetNameField = "spec.target.name"
+)
+
+// GetNamespacedName returns the namespaced name of a resource referenced by a resource in the given namespace
+func GetNamespacedName(namespace string, ref metav1.ObjectMeta) types.NamespacedName {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// CheckKind returns a message describing why the Kind referenced by a resource in the given namespace is not
+// ready, or an empty string if the Kind has been added to topo
+func CheckKind(ctx context.Context, c client.Client, namespace string, ref metav1.ObjectMeta) (string, error) {
+	name := GetNamespacedName(namespace, ref)
+	kind := &v1beta1.Kind{}
+	if err := c.Get(ctx, name, kind); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Sprintf("kind %s not found", name), nil
+		}
+		return "", err
+	}
+	if kind.Status.State != v1beta1.StateAdded {
+		return fmt.Sprintf("kind %s not added", name), nil
+	}
+	return "", nil
+}
+
+// GetKind returns the Kind referenced by a resource in the given namespace, or nil if the Kind does not exist
+func GetKind(ctx context.Context, c client.Reader, namespace string, ref metav1.ObjectMeta) (*v1beta1.Kind, error) {
+	kind := &v1beta1.Kind{}
+	if err := c.Get(ctx, GetNamespacedName(namespace, ref), kind); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return kind, nil
+}
+
+// GetEntity returns the Entity referenced by a resource in the given namespace, or nil if the Entity does not exist
+func GetEntity(ctx context.Context, c client.Reader, namespace string, ref metav1.ObjectMeta) (*v1beta1.Entity, error) {
+	entity := &v1beta1.Entity{}
+	if err := c.Get(ctx, GetNamespacedName(namespace, ref), entity); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+// ResolveEndpoint returns the URI of the entity at the given endpoint of a Relation in the given namespace. The URI
+// of the endpoint is used if set, otherwise the URI is resolved from the Entity referenced by name. An empty URI is
+// returned if the endpoint cannot be resolved.
+func ResolveEndpoint(ctx context.Context, c client.Reader, namespace string, endpoint v1beta1.RelationEndpoint) (string, error) {
+	if endpoint.URI != "" || endpoint.Name == "" {
+		return endpoint.URI, nil
+	}
+	entity, err := GetEntity(ctx, c, namespace, endpoint.ObjectMeta)
+	if err != nil || entity == nil {
+		return "", err
+	}
+	return entity.Spec.URI, nil
+}
+
+// StateChanged returns whether the lifecycle state of the given topology resource changed in an update
+func StateChanged(oldObject, newObject client.Object) bool {
+	return getState(oldObject) != getState(newObject)
+}
+
+// URIChanged returns whether the URI of the given Entity or Relation changed in an update
+func URIChanged(oldObject, newObject client.Object) bool {
+	return getURI(oldObject) != getURI(newObject)
+}
+
+func getState(object client.Object) v1beta1.State {
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		return o.Status.State
+	case *v1beta1.Kind:
+		return o.Status.State
+	case *v1beta1.Relation:
+		return o.Status.State
+	}
+	return ""
+}
+
+func getURI(object client.Object) string {
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		return o.Spec.URI
+	case *v1beta1.Relation:
+		return o.Spec.URI
+	}
+	return ""
+}
@@ -10,34 +10,58 @@ import (
 	"errors"
 	"fmt"
 	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	corev1 "k8s.io/api/core/v1"
-	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// ConnectAddress connects to a gRPC endpoint
-func ConnectAddress(address string) (*grpc.ClientConn, error) {
+// DefaultTLSConfig returns a TLS configuration using the default onos client certificates
+func DefaultTLSConfig() (*tls.Config, error) {
 	cert, err := tls.X509KeyPair([]byte(certs.DefaultClientCrt), []byte(certs.DefaultClientKey))
 	if err != nil {
 		return nil, err
 	}
-	tlsConfig := &tls.Config{
+	return &tls.Config{
 		Certificates:       []tls.Certificate{cert},
 		InsecureSkipVerify: true,
+	}, nil
+}
+
+// ConnectAddress connects to a gRPC endpoint
+func ConnectAddress(address string) (*grpc.ClientConn, error) {
+	tlsConfig, err := DefaultTLSConfig()
+	if err != nil {
+		return nil, err
 	}
-	return grpc.Dial(address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	return ConnectTLS(context.TODO(), address, tlsConfig)
+}
+
+// ConnectTLS connects to a gRPC endpoint using the given TLS configuration
+func ConnectTLS(ctx context.Context, address string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	return grpc.DialContext(ctx, address, opts...)
 }
 
 // ConnectService connects to a gRPC service by name
 func ConnectService(c client.Client, namespace, name string) (*grpc.ClientConn, error) {
+	address, err := getServiceAddress(c, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return ConnectAddress(address)
+}
+
+// getServiceAddress returns the cluster address of a gRPC service by name
+func getServiceAddress(c client.Client, namespace, name string) (string, error) {
 	// Locate the onos-config service
 	services := &corev1.ServiceList{}
 	if err := c.List(context.TODO(), services, client.InNamespace(namespace), client.MatchingLabels{"app": name}); err != nil {
-		return nil, err
+		return "", err
 	} else if len(services.Items) == 0 {
-		return nil, errors.New("service not found")
+		return "", errors.New("service not found")
 	}
 
 	// Find the first matching ClusterIP service
@@ -53,12 +77,24 @@ func ConnectService(c client.Client, namespace, name string) (*grpc.ClientConn,
 
 	// If no ClusterIP service was found, return an error
 	if service == nil {
-		return nil, errors.New("service not found")
+		return "", errors.New("service not found")
 	}
 
-	clusterDomain := os.Getenv("CLUSTER_DOMAIN")
-	if clusterDomain == "" {
-		clusterDomain = "cluster.local"
+	return fmt.Sprintf("%s.%s.svc.%s:%d", service.Name, service.Namespace, k8s.GetClusterDomain(), service.Spec.Ports[0].Port), nil
+}
+
+// WaitForReady waits for the given connection to become ready
+func WaitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection to %s is %s: %v", conn.Target(), state, ctx.Err())
+		}
 	}
-	return ConnectAddress(fmt.Sprintf("%s.%s.svc.%s:%d", service.Name, service.Namespace, clusterDomain, service.Spec.Ports[0].Port))
 }
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/controller/util/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+)
+
+var log = logging.GetLogger("controller", "util", "grpc")
+
+const (
+	// topoScheme is the resolver scheme of pooled connections, whose endpoints are resolved by the manager
+	topoScheme = "topo"
+
+	// roundRobinServiceConfig balances the requests on a pooled connection across the topo service endpoints
+	roundRobinServiceConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+)
+
+// NewConnManager creates a new connection manager
+func NewConnManager(c client.Client, opts ...grpc.DialOption) *ConnManager {
+	return &ConnManager{
+		client: c,
+		opts:   opts,
+		conns:  make(map[types.NamespacedName]*topoConn),
+	}
+}
+
+// ConnManager manages a pool of connections to topo services, sharing a single connection per topo service.
+// Requests on a pooled connection are balanced across the endpoints of the topo service. Pooled connections
+// are health-checked when they are requested and re-dialed if they have failed or if the topo service or its
+// TLS secret have changed. Replaced connections are closed once all references to them have been released.
+type ConnManager struct {
+	client client.Client
+	opts   []grpc.DialOption
+	conns  map[types.NamespacedName]*topoConn
+	mu     sync.Mutex
+}
+
+// topoConn is a pooled connection to a topo service
+type topoConn struct {
+	*grpc.ClientConn
+	resolver *manual.Resolver
+	// endpoints are the endpoints the connection balances requests across
+	endpoints []string
+	// generation is the generation of the topo Service resource the connection was dialed for
+	generation int64
+	// secretVersion is the resourceVersion of the TLS secret the connection was dialed with
+	secretVersion string
+	// refs is the number of unreleased references to the connection
+	refs int
+	// removed indicates the connection has been removed from the pool and is closed when it is no longer referenced
+	removed bool
+}
+
+// isValid returns whether the connection is healthy and was dialed for the given generation of the topo service
+// and version of its TLS secret
+func (c *topoConn) isValid(generation int64, secretVersion string) bool {
+	if c.generation != generation || c.secretVersion != secretVersion {
+		return false
+	}
+	switch c.GetState() {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return false
+	}
+	return true
+}
+
+// setEndpoints updates the endpoints the connection balances requests across
+func (c *topoConn) setEndpoints(endpoints []string) {
+	if equalEndpoints(c.endpoints, endpoints) {
+		return
+	}
+	c.resolver.UpdateState(newResolverState(endpoints))
+	c.endpoints = endpoints
+}
+
+// Conn is a reference to a pooled connection to a topo service. The connection remains open until the reference
+// is released, even if the topo service is re-dialed or disconnected in the meantime.
+type Conn struct {
+	*grpc.ClientConn
+	manager *ConnManager
+	conn    *topoConn
+	once    sync.Once
+}
+
+// Release releases the reference to the connection
+func (c *Conn) Release() {
+	c.once.Do(func() {
+		c.manager.release(c.conn)
+	})
+}
+
+// Connect returns a reference to a connection to the topo service with the given name. If a topo Service resource
+// with the given name exists, the connection is balanced across its endpoints; otherwise the Kubernetes Service
+// with the given name is used. The returned connection is shared: callers must release it when done rather than
+// closing it.
+func (m *ConnManager) Connect(ctx context.Context, name types.NamespacedName) (*Conn, error) {
+	service, err := services.GetService(ctx, m.client, name.Namespace, name.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []string
+	var generation int64
+	var secretVersion string
+	tlsConfig, err := DefaultTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if service != nil {
+		endpoints, err = services.GetEndpoints(ctx, m.client, service)
+		if err != nil {
+			return nil, err
+		} else if len(endpoints) == 0 {
+			return nil, fmt.Errorf("no endpoints found for topo service %s", name.Name)
+		}
+		generation = service.Generation
+
+		secret, err := services.GetTLSSecret(ctx, m.client, service)
+		if err != nil {
+			return nil, err
+		} else if secret != nil {
+			secretVersion = secret.ResourceVersion
+			if tlsConfig, err = services.GetTLSConfig(service, secret); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		address, err := getServiceAddress(m.client, name.Namespace, name.Name)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = []string{address}
+	}
+
+	if conn := m.get(name, endpoints, generation, secretVersion); conn != nil {
+		return conn, nil
+	}
+
+	// Dial without holding the lock so connecting to one topo service does not block the others
+	r := manual.NewBuilderWithScheme(topoScheme)
+	r.InitialState(newResolverState(endpoints))
+	opts := append([]grpc.DialOption{
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+	}, m.opts...)
+	log.Infof("Connecting to topo service %s at %v", name, endpoints)
+	clientConn, err := ConnectTLS(ctx, fmt.Sprintf("%s:///%s.%s", topoScheme, name.Name, name.Namespace), tlsConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Use the connection of a concurrent caller if it is valid
+	if conn, ok := m.conns[name]; ok {
+		if conn.isValid(generation, secretVersion) {
+			_ = clientConn.Close()
+			return m.acquire(conn, endpoints), nil
+		}
+		m.remove(name, conn)
+	}
+	conn := &topoConn{
+		ClientConn:    clientConn,
+		resolver:      r,
+		endpoints:     endpoints,
+		generation:    generation,
+		secretVersion: secretVersion,
+	}
+	m.conns[name] = conn
+	return m.acquire(conn, endpoints), nil
+}
+
+// get returns a reference to the pooled connection to the topo service with the given name if it is valid,
+// removing it from the pool otherwise
+func (m *ConnManager) get(name types.NamespacedName, endpoints []string, generation int64, secretVersion string) *Conn {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conn, ok := m.conns[name]
+	if !ok {
+		return nil
+	}
+	if conn.isValid(generation, secretVersion) {
+		return m.acquire(conn, endpoints)
+	}
+	log.Infof("Re-dialing topo service %s", name)
+	m.remove(name, conn)
+	return nil
+}
+
+// acquire returns a new reference to the given pooled connection, updating its endpoints.
+// The caller must hold the lock.
+func (m *ConnManager) acquire(conn *topoConn, endpoints []string) *Conn {
+	conn.setEndpoints(endpoints)
+	conn.refs++
+	return &Conn{
+		ClientConn: conn.ClientConn,
+		manager:    m,
+		conn:       conn,
+	}
+}
+
+// release releases a reference to the given connection, closing it if it has been removed from the pool and
+// is no longer referenced
+func (m *ConnManager) release(conn *topoConn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conn.refs--
+	if conn.removed && conn.refs == 0 {
+		_ = conn.Close()
+	}
+}
+
+// remove removes the given connection from the pool, closing it if it is not referenced.
+// The caller must hold the lock.
+func (m *ConnManager) remove(name types.NamespacedName, conn *topoConn) {
+	delete(m.conns, name)
+	conn.removed = true
+	if conn.refs == 0 {
+		_ = conn.Close()
+	}
+}
+
+// Disconnect removes the pooled connection to the topo service with the given name, closing it once all
+// references to it have been released
+func (m *ConnManager) Disconnect(name types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conn, ok := m.conns[name]; ok {
+		log.Infof("Disconnecting from topo service %s", name)
+		m.remove(name, conn)
+	}
+}
+
+// newResolverState returns the resolver state of a connection to the given endpoints. The endpoints are used as
+// the authority of their connections, as if they had been dialed directly.
+func newResolverState(endpoints []string) resolver.State {
+	addresses := make([]resolver.Address, len(endpoints))
+	for i, endpoint := range endpoints {
+		addresses[i] = resolver.Address{
+			Addr:       endpoint,
+			ServerName: endpoint,
+		}
+	}
+	return resolver.State{Addresses: addresses}
+}
+
+// equalEndpoints returns whether the given sorted lists of endpoints are equal
+func equalEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"google.golang.org/grpc/connectivity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func TestConnManager(t *testing.T) {
+	t.Setenv("CONTROLLER_NAMESPACE", "micro-onos")
+
+	scheme := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "onos-topo-tls",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(certs.DefaultClientCrt),
+			corev1.TLSPrivateKeyKey: []byte(certs.DefaultClientKey),
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&v1beta1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "micro-onos",
+					Name:      "onos-topo",
+				},
+				Spec: v1beta1.ServiceSpec{
+					Address: "onos-topo.micro-onos.svc:5150",
+					TLS: &v1beta1.ServiceTLS{
+						SecretName:         secret.Name,
+						InsecureSkipVerify: true,
+					},
+				},
+			},
+			secret,
+		).
+		Build()
+
+	ctx := context.TODO()
+	name := types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}
+	conns := NewConnManager(c)
+
+	conn1, err := conns.Connect(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn2, err := conns.Connect(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn1.ClientConn != conn2.ClientConn {
+		t.Error("expected connections to the same topo service to be shared")
+	}
+
+	// Rotating the TLS secret re-dials the topo service without closing the connection in use
+	secret.Data[corev1.TLSCertKey] = []byte(certs.DefaultClientCrt + "\n")
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	conn3, err := conns.Connect(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn3.ClientConn == conn1.ClientConn {
+		t.Error("expected topo service to be re-dialed when its TLS secret changed")
+	}
+	if conn1.GetState() == connectivity.Shutdown {
+		t.Error("expected replaced connection to remain open while referenced")
+	}
+	conn1.Release()
+	conn1.Release()
+	if conn2.GetState() == connectivity.Shutdown {
+		t.Error("expected replaced connection to remain open while referenced")
+	}
+	conn2.Release()
+	if conn2.GetState() != connectivity.Shutdown {
+		t.Error("expected replaced connection to be closed once released")
+	}
+
+	// Disconnecting closes the connection once it is released
+	conns.Disconnect(name)
+	if conn3.GetState() == connectivity.Shutdown {
+		t.Error("expected disconnected connection to remain open while referenced")
+	}
+	conn3.Release()
+	if conn3.GetState() != connectivity.Shutdown {
+		t.Error("expected disconnected connection to be closed once released")
+	}
+}
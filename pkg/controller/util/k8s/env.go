@@ -4,7 +4,12 @@
 
 package k8s
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
 
 // Scope :
 type Scope string
@@ -21,11 +26,26 @@ const (
 	nameEnv      = "CONTROLLER_NAME"
 	namespaceEnv = "CONTROLLER_NAMESPACE"
 	scopeEnv     = "CONTROLLER_SCOPE"
+
+	serviceAccountEnv = "CONTROLLER_SERVICE_ACCOUNT"
+
+	clusterDomainEnv = "CLUSTER_DOMAIN"
+
+	resyncIntervalEnv = "CONTROLLER_RESYNC_INTERVAL"
+
+	labelPrefixesEnv = "CONTROLLER_LABEL_PREFIXES"
+	labelKeysEnv     = "CONTROLLER_LABEL_KEYS"
 )
 
 const (
 	defaultNamespace = "kube-system"
 	defaultScope     = ClusterScope
+
+	defaultServiceAccount = "onos-operator"
+
+	defaultClusterDomain = "cluster.local"
+
+	defaultResyncInterval = 5 * time.Minute
 )
 
 // GetName :
@@ -54,3 +74,60 @@ func GetScope() Scope {
 	}
 	return defaultScope
 }
+
+// GetServiceAccount returns the name of the service account of the operator
+func GetServiceAccount() string {
+	serviceAccount := os.Getenv(serviceAccountEnv)
+	if serviceAccount != "" {
+		return serviceAccount
+	}
+	return defaultServiceAccount
+}
+
+// GetServiceAccountUsername returns the name of the user the operator authenticates as with its service account
+func GetServiceAccountUsername() string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", GetNamespace(), GetServiceAccount())
+}
+
+// GetClusterDomain returns the DNS domain of the cluster
+func GetClusterDomain() string {
+	clusterDomain := os.Getenv(clusterDomainEnv)
+	if clusterDomain != "" {
+		return clusterDomain
+	}
+	return defaultClusterDomain
+}
+
+// GetResyncInterval :
+func GetResyncInterval() time.Duration {
+	interval := os.Getenv(resyncIntervalEnv)
+	if interval == "" {
+		return defaultResyncInterval
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration < 0 {
+		return defaultResyncInterval
+	}
+	return duration
+}
+
+// GetLabelPrefixes returns the prefixes of the keys of the resource labels and annotations copied to topo labels
+func GetLabelPrefixes() []string {
+	return getList(labelPrefixesEnv)
+}
+
+// GetLabelKeys returns the keys of the resource labels and annotations copied to topo labels
+func GetLabelKeys() []string {
+	return getList(labelKeysEnv)
+}
+
+// getList returns the non-empty comma separated values of the given environment variable
+func getList(env string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(env), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
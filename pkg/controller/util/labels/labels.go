@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package labels
+
+import (
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"strings"
+)
+
+// reserved are the keys of the topo labels and annotations used by the operator itself, which are never copied
+var reserved = map[string]bool{
+	owners.ManagedByLabel:  true,
+	owners.OwnerLabel:      true,
+	owners.OwnerUIDLabel:   true,
+	owners.AdoptAnnotation: true,
+}
+
+// Mapping selects the labels and annotations of resources that are copied to the labels of their topo objects
+type Mapping struct {
+	// Prefixes are the key prefixes of the labels and annotations to copy
+	Prefixes []string
+	// Keys are the keys of the labels and annotations to copy
+	Keys []string
+}
+
+// GetMapping returns the mapping configured for the operator
+func GetMapping() Mapping {
+	return Mapping{
+		Prefixes: k8s.GetLabelPrefixes(),
+		Keys:     k8s.GetLabelKeys(),
+	}
+}
+
+// Matches returns whether the mapping selects the label or annotation with the given key
+func (m Mapping) Matches(key string) bool {
+	if reserved[key] {
+		return false
+	}
+	for _, k := range m.Keys {
+		if key == k {
+			return true
+		}
+	}
+	for _, prefix := range m.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the topo labels of the given resource selected by the mapping. A label takes precedence over an
+// annotation with the same key.
+func (m Mapping) Get(object metav1.Object) map[string]string {
+	var labels map[string]string
+	for _, values := range []map[string]string{object.GetAnnotations(), object.GetLabels()} {
+		for key, value := range values {
+			if !m.Matches(key) {
+				continue
+			}
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// Apply sets the given labels on the object and removes the previously applied labels that are no longer
+// present in the given labels. Labels that were not applied by the operator are left untouched.
+func Apply(object *topo.Object, labels map[string]string, applied map[string]string) {
+	if object.Labels == nil {
+		object.Labels = make(map[string]string)
+	}
+	for key, value := range labels {
+		object.Labels[key] = value
+	}
+	for _, key := range Removed(labels, applied) {
+		delete(object.Labels, key)
+	}
+}
+
+// Removed returns the sorted keys of the previously applied labels that are no longer present in the given labels
+func Removed(labels map[string]string, applied map[string]string) []string {
+	var keys []string
+	for key := range applied {
+		if _, ok := labels[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Equal returns whether the given labels have the same keys and values
+func Equal(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, aValue := range a {
+		if bValue, ok := b[key]; !ok || aValue != bValue {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the sorted keys of the given labels that are missing from or differ in the given object, along
+// with the previously applied labels that have been removed from the given labels but are still present in the
+// object
+func Diff(object *topo.Object, labels map[string]string, applied map[string]string) []string {
+	var keys []string
+	for key, value := range labels {
+		if current, ok := object.Labels[key]; !ok || current != value {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range Removed(labels, applied) {
+		if _, ok := object.Labels[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
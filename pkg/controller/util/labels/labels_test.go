@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package labels
+
+import (
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+// TestGet verifies the labels and annotations of a resource selected by the mapping, which never include the
+// keys reserved for ownership
+func TestGet(t *testing.T) {
+	t.Setenv("CONTROLLER_LABEL_PREFIXES", "topo.onosproject.org/, example.com/")
+	t.Setenv("CONTROLLER_LABEL_KEYS", "rack")
+	mapping := GetMapping()
+
+	entity := &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "switch-1",
+			Labels: map[string]string{
+				"rack":                     "r1",
+				"row":                      "2",
+				"example.com/tier":         "edge",
+				owners.ManagedByLabel:      "onos-operator",
+				owners.OwnerLabel:          "Entity.micro-onos.switch-2",
+				owners.OwnerUIDLabel:       "1234",
+				"topo.onosproject.org/pod": "p1",
+			},
+			Annotations: map[string]string{
+				"example.com/tier":     "core",
+				"example.com/contact":  "noc",
+				owners.AdoptAnnotation: "true",
+			},
+		},
+	}
+	expected := map[string]string{
+		"rack":                     "r1",
+		"example.com/tier":         "edge",
+		"example.com/contact":      "noc",
+		"topo.onosproject.org/pod": "p1",
+	}
+	if labels := mapping.Get(entity); !Equal(labels, expected) {
+		t.Errorf("expected labels %v, got %v", expected, labels)
+	}
+
+	tests := []struct {
+		key     string
+		matches bool
+	}{
+		{key: "rack", matches: true},
+		{key: "racks"},
+		{key: "example.com/tier", matches: true},
+		{key: "topo.onosproject.org/pod", matches: true},
+		{key: owners.ManagedByLabel},
+		{key: owners.OwnerLabel},
+		{key: owners.OwnerUIDLabel},
+		{key: owners.AdoptAnnotation},
+	}
+	for _, test := range tests {
+		if matches := mapping.Matches(test.key); matches != test.matches {
+			t.Errorf("expected %s to match %t", test.key, test.matches)
+		}
+	}
+
+	if labels := (Mapping{}).Get(entity); labels != nil {
+		t.Errorf("expected no labels to be selected by an empty mapping, got %v", labels)
+	}
+}
+
+// TestApply verifies that applying labels to a topo object only removes the labels previously applied by the
+// operator
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   map[string]string
+		labels   map[string]string
+		applied  map[string]string
+		expected map[string]string
+		diff     []string
+		removed  []string
+	}{
+		{
+			name:     "added",
+			labels:   map[string]string{"rack": "r1"},
+			expected: map[string]string{"rack": "r1"},
+			diff:     []string{"rack"},
+		},
+		{
+			name:     "unchanged",
+			object:   map[string]string{"rack": "r1", "pod": "p1"},
+			labels:   map[string]string{"rack": "r1"},
+			applied:  map[string]string{"rack": "r1"},
+			expected: map[string]string{"rack": "r1", "pod": "p1"},
+		},
+		{
+			name:     "changed",
+			object:   map[string]string{"rack": "r2"},
+			labels:   map[string]string{"rack": "r1"},
+			applied:  map[string]string{"rack": "r1"},
+			expected: map[string]string{"rack": "r1"},
+			diff:     []string{"rack"},
+		},
+		{
+			name:     "removed",
+			object:   map[string]string{"rack": "r1", "row": "2", "pod": "p1"},
+			labels:   map[string]string{"rack": "r1"},
+			applied:  map[string]string{"rack": "r1", "row": "2", "tier": "edge"},
+			expected: map[string]string{"rack": "r1", "pod": "p1"},
+			diff:     []string{"row"},
+			removed:  []string{"row", "tier"},
+		},
+		{
+			name:     "ownership labels",
+			object:   map[string]string{owners.ManagedByLabel: "onos-operator", owners.OwnerLabel: "Entity.micro-onos.switch-1"},
+			labels:   map[string]string{"rack": "r1"},
+			applied:  map[string]string{"rack": "r1"},
+			expected: map[string]string{owners.ManagedByLabel: "onos-operator", owners.OwnerLabel: "Entity.micro-onos.switch-1", "rack": "r1"},
+			diff:     []string{"rack"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := &topo.Object{Labels: test.object}
+			if diff := Diff(object, test.labels, test.applied); !equalKeys(diff, test.diff) {
+				t.Errorf("expected diff %v, got %v", test.diff, diff)
+			}
+			if removed := Removed(test.labels, test.applied); !equalKeys(removed, test.removed) {
+				t.Errorf("expected removed %v, got %v", test.removed, removed)
+			}
+			Apply(object, test.labels, test.applied)
+			if !Equal(object.Labels, test.expected) {
+				t.Errorf("expected labels %v, got %v", test.expected, object.Labels)
+			}
+			if diff := Diff(object, test.labels, test.applied); len(diff) > 0 {
+				t.Errorf("expected no diff once applied, got %v", diff)
+			}
+		})
+	}
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
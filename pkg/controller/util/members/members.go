@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package members
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var log = logging.GetLogger("controller", "util", "members")
+
+// ApplyKind creates or updates the Kind defined by the given template as a member of the given owner, labeling
+// the Kind with the name of the owner under the given label
+func ApplyKind(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, label string, template v1beta1.KindTemplate) (*v1beta1.Kind, error) {
+	kind := &v1beta1.Kind{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      template.Name,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, c, kind, func() error {
+		if err := setMember(scheme, kind, owner, label, template.Labels); err != nil {
+			return err
+		}
+		kind.Spec = template.Spec
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Infof("Kind %s.%s %s", kind.Name, kind.Namespace, result)
+	}
+	return kind, nil
+}
+
+// ApplyEntity creates or updates the Entity defined by the given template as a member of the given owner,
+// labeling the Entity with the name of the owner under the given label
+func ApplyEntity(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, label string, template v1beta1.EntityTemplate) (*v1beta1.Entity, error) {
+	entity := &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      template.Name,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, c, entity, func() error {
+		if err := setMember(scheme, entity, owner, label, template.Labels); err != nil {
+			return err
+		}
+		entity.Spec = template.Spec
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Infof("Entity %s.%s %s", entity.Name, entity.Namespace, result)
+	}
+	return entity, nil
+}
+
+// ApplyRelation creates or updates the Relation defined by the given template as a member of the given owner,
+// labeling the Relation with the name of the owner under the given label
+func ApplyRelation(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, label string, template v1beta1.RelationTemplate) (*v1beta1.Relation, error) {
+	relation := &v1beta1.Relation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      template.Name,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, c, relation, func() error {
+		if err := setMember(scheme, relation, owner, label, template.Labels); err != nil {
+			return err
+		}
+		relation.Spec = template.Spec
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Infof("Relation %s.%s %s", relation.Name, relation.Namespace, result)
+	}
+	return relation, nil
+}
+
+// setMember labels the given resource as a member of the given owner and makes the owner its controller.
+// Existing resources that are not controlled by the owner are never taken over.
+func setMember(scheme *runtime.Scheme, object client.Object, owner client.Object, label string, labels map[string]string) error {
+	if object.GetResourceVersion() != "" && !metav1.IsControlledBy(object, owner) {
+		return fmt.Errorf("%s already exists and is not a member of %s", object.GetName(), owner.GetName())
+	}
+	objectLabels := object.GetLabels()
+	if objectLabels == nil {
+		objectLabels = make(map[string]string)
+	}
+	for key, value := range labels {
+		objectLabels[key] = value
+	}
+	objectLabels[label] = owner.GetName()
+	object.SetLabels(objectLabels)
+	return controllerutil.SetControllerReference(owner, object, scheme)
+}
+
+// Prune deletes the members of the given owner listed in the given list type whose names are not in keep
+func Prune(ctx context.Context, c client.Client, owner client.Object, label string, list client.ObjectList, keep map[string]bool) error {
+	if err := c.List(ctx, list, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{label: owner.GetName()}); err != nil {
+		return err
+	}
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, o := range objects {
+		object := o.(client.Object)
+		if keep[object.GetName()] || !metav1.IsControlledBy(object, owner) || object.GetDeletionTimestamp() != nil {
+			continue
+		}
+		log.Infof("Pruning %s.%s removed from %s", object.GetName(), object.GetNamespace(), owner.GetName())
+		if err := c.Delete(ctx, object); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxFailures is the maximum number of failed members reported in the status of a resource
+const maxFailures = 20
+
+// Progress tracks the members of a resource as they are applied
+type Progress struct {
+	// Members is the number of members of the resource
+	Members int32
+	// Applied is the number of members that have been added to topo
+	Applied int32
+	// Failures lists the members that could not be added to topo
+	Failures []string
+}
+
+// Record records the state of the given member after it was applied, returning whether it has been added to topo
+func (p *Progress) Record(kind string, name string, status *v1beta1.ObjectStatus, err error) bool {
+	if err != nil {
+		p.Failures = append(p.Failures, fmt.Sprintf("%s %s: %s", kind, name, err))
+		return false
+	}
+	if failure := getFailure(status); failure != "" {
+		p.Failures = append(p.Failures, fmt.Sprintf("%s %s: %s", kind, name, failure))
+	}
+	if status.State != v1beta1.StateAdded {
+		return false
+	}
+	p.Applied++
+	return true
+}
+
+// GetProgress returns the applied members as "<applied>/<members>"
+func (p *Progress) GetProgress() string {
+	return fmt.Sprintf("%d/%d", p.Applied, p.Members)
+}
+
+// GetFailures returns the failed members to report in the status of the resource
+func (p *Progress) GetFailures() []string {
+	if len(p.Failures) <= maxFailures {
+		return p.Failures
+	}
+	failures := append([]string{}, p.Failures[:maxFailures]...)
+	return append(failures, fmt.Sprintf("and %d more", len(p.Failures)-maxFailures))
+}
+
+// GetReadyCondition returns the Ready condition of the given generation of the resource
+func (p *Progress) GetReadyCondition(generation int64) metav1.Condition {
+	ready := metav1.Condition{
+		Type:               v1beta1.ConditionReady,
+		ObservedGeneration: generation,
+	}
+	switch {
+	case len(p.Failures) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = v1beta1.ReasonApplyFailed
+		ready.Message = fmt.Sprintf("%d of %d members failed", len(p.Failures), p.Members)
+	case p.Applied < p.Members:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = v1beta1.ReasonApplying
+		ready.Message = fmt.Sprintf("%d of %d members applied", p.Applied, p.Members)
+	default:
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = v1beta1.ReasonApplied
+	}
+	return ready
+}
+
+// getFailure returns the reason the given member could not be added to topo, or an empty string if the member
+// has been added or is still being added
+func getFailure(status *v1beta1.ObjectStatus) string {
+	condition := meta.FindStatusCondition(status.Conditions, v1beta1.ConditionSynced)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		return ""
+	}
+	switch condition.Reason {
+	case v1beta1.ReasonSyncFailed, v1beta1.ReasonOwnershipConflict, v1beta1.ReasonServiceNotAllowed, v1beta1.ReasonInvalidAspects:
+		return condition.Message
+	}
+	return ""
+}
@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package members
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+const topologyLabel = "topo.onosproject.org/topology"
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func newTopology() *v1beta1.Topology {
+	return &v1beta1.Topology{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "lab",
+			UID:       "1234",
+		},
+	}
+}
+
+// TestApplyEntity verifies that members are created and updated under the control of their owner, and that
+// existing resources that are not members of the owner are never taken over
+func TestApplyEntity(t *testing.T) {
+	scheme := newScheme(t)
+	topology := newTopology()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			topology,
+			&v1beta1.Entity{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "micro-onos",
+					Name:      "switch-2",
+				},
+			},
+		).
+		Build()
+	ctx := context.TODO()
+
+	template := v1beta1.EntityTemplate{
+		Name:   "switch-1",
+		Labels: map[string]string{"rack": "1"},
+		Spec: v1beta1.EntitySpec{
+			URI:  "p4rt:switch-1",
+			Kind: metav1.ObjectMeta{Name: "switch"},
+		},
+	}
+	if _, err := ApplyEntity(ctx, c, scheme, topology, topologyLabel, template); err != nil {
+		t.Fatal(err)
+	}
+	entity := &v1beta1.Entity{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "micro-onos", Name: "switch-1"}, entity); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(entity, topology) {
+		t.Error("expected member to be controlled by its owner")
+	}
+	if entity.Labels[topologyLabel] != "lab" || entity.Labels["rack"] != "1" {
+		t.Errorf("expected member to be labeled with its owner and template labels, got %v", entity.Labels)
+	}
+
+	// Applying a changed template updates the member
+	template.Spec.URI = "p4rt:switch-1/1"
+	if _, err := ApplyEntity(ctx, c, scheme, topology, topologyLabel, template); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "micro-onos", Name: "switch-1"}, entity); err != nil {
+		t.Fatal(err)
+	}
+	if entity.Spec.URI != "p4rt:switch-1/1" {
+		t.Errorf("expected member to be updated, got URI %s", entity.Spec.URI)
+	}
+
+	// Resources that are not members of the owner are not taken over
+	template.Name = "switch-2"
+	if _, err := ApplyEntity(ctx, c, scheme, topology, topologyLabel, template); err == nil {
+		t.Error("expected existing entity not to be taken over")
+	}
+}
+
+// TestPrune verifies that only the members of an owner that are no longer defined are deleted
+func TestPrune(t *testing.T) {
+	scheme := newScheme(t)
+	topology := newTopology()
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(topology).
+		Build()
+	ctx := context.TODO()
+
+	for _, name := range []string{"switch-1", "switch-2"} {
+		template := v1beta1.EntityTemplate{
+			Name: name,
+			Spec: v1beta1.EntitySpec{
+				URI:  "p4rt:" + name,
+				Kind: metav1.ObjectMeta{Name: "switch"},
+			},
+		}
+		if _, err := ApplyEntity(ctx, c, scheme, topology, topologyLabel, template); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A resource labeled with the owner that it does not control is not a member
+	if err := c.Create(ctx, &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "switch-3",
+			Labels:    map[string]string{topologyLabel: "lab"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Prune(ctx, c, topology, topologyLabel, &v1beta1.EntityList{}, map[string]bool{"switch-1": true}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		deleted bool
+	}{
+		{name: "switch-1"},
+		{name: "switch-2", deleted: true},
+		{name: "switch-3"},
+	}
+	for _, test := range tests {
+		err := c.Get(ctx, types.NamespacedName{Namespace: "micro-onos", Name: test.name}, &v1beta1.Entity{})
+		if test.deleted && !k8serrors.IsNotFound(err) {
+			t.Errorf("expected %s to be pruned", test.name)
+		} else if !test.deleted && err != nil {
+			t.Errorf("expected %s not to be pruned: %v", test.name, err)
+		}
+	}
+}
+
+// TestRecord verifies the members counted as applied and failed for the states of the members
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  func(status *v1beta1.ObjectStatus)
+		err     error
+		applied bool
+		failure string
+	}{
+		{
+			name: "added",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetState(status, v1beta1.StateAdded, 1)
+				conditions.SetSynced(status, 1)
+			},
+			applied: true,
+		},
+		{
+			name: "pending",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetPending(status, 1, v1beta1.ReasonServiceNotFound, "topo service not found")
+			},
+		},
+		{
+			name: "waiting for dependencies",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetSyncFailed(status, 1, v1beta1.ReasonWaitingForDependencies, errors.New("kind not found"))
+			},
+		},
+		{
+			name: "sync failed",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetSyncFailed(status, 1, v1beta1.ReasonSyncFailed, errors.New("connection refused"))
+			},
+			failure: "Entity switch-1: connection refused",
+		},
+		{
+			name: "ownership conflict",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetSyncFailed(status, 1, v1beta1.ReasonOwnershipConflict, errors.New("owned by Entity tenant-a/switch-1"))
+			},
+			failure: "Entity switch-1: owned by Entity tenant-a/switch-1",
+		},
+		{
+			name: "failed after added",
+			status: func(status *v1beta1.ObjectStatus) {
+				conditions.SetState(status, v1beta1.StateAdded, 1)
+				conditions.SetSyncFailed(status, 2, v1beta1.ReasonSyncFailed, errors.New("invalid aspect"))
+			},
+			applied: true,
+			failure: "Entity switch-1: invalid aspect",
+		},
+		{
+			name:    "apply failed",
+			status:  func(status *v1beta1.ObjectStatus) {},
+			err:     errors.New("already exists"),
+			failure: "Entity switch-1: already exists",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := &v1beta1.ObjectStatus{}
+			test.status(status)
+			progress := &Progress{Members: 1}
+			if applied := progress.Record("Entity", "switch-1", status, test.err); applied != test.applied {
+				t.Errorf("expected applied to be %t", test.applied)
+			}
+			if test.applied && progress.Applied != 1 {
+				t.Errorf("expected 1 applied member, got %d", progress.Applied)
+			} else if !test.applied && progress.Applied != 0 {
+				t.Errorf("expected no applied members, got %d", progress.Applied)
+			}
+			if test.failure == "" && len(progress.Failures) > 0 {
+				t.Errorf("expected no failures, got %v", progress.Failures)
+			} else if test.failure != "" && (len(progress.Failures) != 1 || progress.Failures[0] != test.failure) {
+				t.Errorf("expected failure %q, got %v", test.failure, progress.Failures)
+			}
+		})
+	}
+}
+
+// TestGetReadyCondition verifies the Ready condition of a resource for the progress of its members
+func TestGetReadyCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress Progress
+		status   metav1.ConditionStatus
+		reason   string
+		message  string
+	}{
+		{
+			name:     "applied",
+			progress: Progress{Members: 3, Applied: 3},
+			status:   metav1.ConditionTrue,
+			reason:   v1beta1.ReasonApplied,
+		},
+		{
+			name:     "no members",
+			progress: Progress{},
+			status:   metav1.ConditionTrue,
+			reason:   v1beta1.ReasonApplied,
+		},
+		{
+			name:     "applying",
+			progress: Progress{Members: 3, Applied: 1},
+			status:   metav1.ConditionFalse,
+			reason:   v1beta1.ReasonApplying,
+			message:  "1 of 3 members applied",
+		},
+		{
+			name:     "failed",
+			progress: Progress{Members: 3, Applied: 2, Failures: []string{"Entity switch-1: connection refused"}},
+			status:   metav1.ConditionFalse,
+			reason:   v1beta1.ReasonApplyFailed,
+			message:  "1 of 3 members failed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ready := test.progress.GetReadyCondition(2)
+			if ready.Type != v1beta1.ConditionReady || ready.ObservedGeneration != 2 {
+				t.Errorf("unexpected condition %s of generation %d", ready.Type, ready.ObservedGeneration)
+			}
+			if ready.Status != test.status || ready.Reason != test.reason || ready.Message != test.message {
+				t.Errorf("expected %s %s %q, got %s %s %q", test.status, test.reason, test.message, ready.Status, ready.Reason, ready.Message)
+			}
+		})
+	}
+}
+
+// TestGetFailures verifies that the failures reported in the status of a resource are truncated
+func TestGetFailures(t *testing.T) {
+	progress := &Progress{}
+	for i := 1; i <= maxFailures+5; i++ {
+		progress.Failures = append(progress.Failures, fmt.Sprintf("Entity switch-%d: connection refused", i))
+	}
+	failures := progress.GetFailures()
+	if len(failures) != maxFailures+1 {
+		t.Fatalf("expected %d failures, got %d", maxFailures+1, len(failures))
+	}
+	if failures[maxFailures] != "and 5 more" {
+		t.Errorf("expected truncated failures to be summarized, got %q", failures[maxFailures])
+	}
+	if len(progress.Failures) != maxFailures+5 {
+		t.Error("expected recorded failures not to be modified")
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mirrors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+const (
+	// MirroredLabel is the label marking resources mirrored from onos-topo
+	MirroredLabel = "topo.onosproject.org/mirrored"
+	// ServiceLabel is the label recording the name of the topo Service a resource is mirrored from
+	ServiceLabel = "topo.onosproject.org/mirror-service"
+	// IDAnnotation is the annotation recording the topo ID of a mirrored resource
+	IDAnnotation = "topo.onosproject.org/id"
+)
+
+// IDField is the name of the index of topology resources by topo ID
+const IDField = "topo.id"
+
+const (
+	maxNameLength = 63
+	hashLength    = 10
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// IsMirrored returns whether the given resource is mirrored from onos-topo
+func IsMirrored(object metav1.Object) bool {
+	return object.GetLabels()[MirroredLabel] == "true"
+}
+
+// GetID returns the topo ID of the given topology resource, or the ID recorded in the annotations of a
+// mirrored resource
+func GetID(object client.Object) string {
+	if id, ok := object.GetAnnotations()[IDAnnotation]; ok {
+		return id
+	}
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		return o.Spec.URI
+	case *v1beta1.Kind:
+		return o.Name
+	case *v1beta1.Relation:
+		return o.Spec.URI
+	}
+	return ""
+}
+
+// GetName returns the name of the resource mirroring the topo object with the given ID. IDs that are valid
+// resource names are used as is; other IDs are sanitized and suffixed with a hash of the ID.
+func GetName(id string) string {
+	if len(id) <= maxNameLength && len(validation.IsDNS1123Subdomain(id)) == 0 {
+		return id
+	}
+	hash := sha256.Sum256([]byte(id))
+	suffix := hex.EncodeToString(hash[:])[:hashLength]
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(id), "-")
+	if len(name) > maxNameLength-hashLength-1 {
+		name = name[:maxNameLength-hashLength-1]
+	}
+	name = strings.Trim(name, ".-")
+	if name == "" {
+		return suffix
+	}
+	return name + "-" + suffix
+}
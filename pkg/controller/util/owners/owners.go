@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package owners
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+const (
+	// ManagedByLabel is the topo label marking objects created by the operator
+	ManagedByLabel = "topo.onosproject.org/managed-by"
+	// OwnerLabel is the topo label recording the resource owning an object as <kind>/<namespace>/<name>
+	OwnerLabel = "topo.onosproject.org/owner"
+	// OwnerUIDLabel is the topo label recording the UID of the resource owning an object
+	OwnerUIDLabel = "topo.onosproject.org/owner-uid"
+	// AdoptAnnotation is the annotation allowing a resource to take over objects owned by others
+	AdoptAnnotation = "topo.onosproject.org/adopt"
+)
+
+const managedBy = "onos-operator"
+
+// GetOwnerRef returns the reference to the given Kind, Entity or Relation resource recorded in the topo
+// objects it owns
+func GetOwnerRef(owner client.Object) string {
+	var kind string
+	switch owner.(type) {
+	case *v1beta1.Entity:
+		kind = "Entity"
+	case *v1beta1.Kind:
+		kind = "Kind"
+	case *v1beta1.Relation:
+		kind = "Relation"
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, owner.GetNamespace(), owner.GetName())
+}
+
+// ParseOwnerRef returns the kind, namespace and name of the resource referenced by the given owner reference
+func ParseOwnerRef(ref string) (string, string, string, bool) {
+	parts := strings.SplitN(ref, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// IsManaged returns whether the given topo object was created by the operator
+func IsManaged(object *topo.Object) bool {
+	return object.Labels[ManagedByLabel] == managedBy
+}
+
+// GetOwner returns the reference to the resource owning the given topo object, or an empty string if the
+// object is not managed by the operator
+func GetOwner(object *topo.Object) string {
+	if !IsManaged(object) {
+		return ""
+	}
+	return object.Labels[OwnerLabel]
+}
+
+// IsOwner returns whether the given resource owns the given topo object
+func IsOwner(object *topo.Object, owner client.Object) bool {
+	return GetOwner(object) == GetOwnerRef(owner) && object.Labels[OwnerUIDLabel] == string(owner.GetUID())
+}
+
+// SetOwner records the given resource as the owner of the given topo object
+func SetOwner(object *topo.Object, owner client.Object) {
+	if object.Labels == nil {
+		object.Labels = make(map[string]string)
+	}
+	object.Labels[ManagedByLabel] = managedBy
+	object.Labels[OwnerLabel] = GetOwnerRef(owner)
+	object.Labels[OwnerUIDLabel] = string(owner.GetUID())
+}
+
+// RemoveOwner releases the ownership of the given topo object, leaving it to be claimed by another resource
+func RemoveOwner(object *topo.Object) {
+	delete(object.Labels, OwnerLabel)
+	delete(object.Labels, OwnerUIDLabel)
+}
+
+// IsAdopting returns whether the given resource is annotated to adopt the topo object it defines
+func IsAdopting(owner client.Object) bool {
+	return owner.GetAnnotations()[AdoptAnnotation] == "true"
+}
+
+// CanClaim returns whether a resource with the given status may claim the given topo object that is not managed by
+// the operator, which is only the case for the object at the applied URI of a resource whose object was added by a
+// previous version of the operator. Any other unmanaged object, e.g. at a URI the resource is migrated to or
+// re-created by another tool, is only claimed if the resource is annotated to adopt it.
+func CanClaim(status *v1beta1.ObjectStatus, object *topo.Object, appliedURI string) bool {
+	return status.Claimed && string(object.ID) == appliedURI
+}
+
+// ClaimLegacy marks the given resource as claiming its topo object if the object was added by a previous version
+// of the operator, and returns whether the status of the resource has changed. Previous versions added the given
+// finalizer to the resources they added to topo, but did not set any status conditions, which this version sets
+// before adding the finalizer.
+func ClaimLegacy(owner client.Object, finalizer string, status *v1beta1.ObjectStatus) bool {
+	if status.Claimed || status.LastSyncTime != nil || len(status.Conditions) > 0 || !k8s.HasFinalizer(owner, finalizer) {
+		return false
+	}
+	status.Claimed = true
+	return true
+}
+
+// Check returns an error if the given topo object is owned by another resource than the given resource,
+// unless the resource is annotated to adopt it. Objects orphaned by the operator may be claimed by any resource,
+// while other objects that are not managed by the operator are only claimed if claim is true.
+func Check(object *topo.Object, owner client.Object, claim bool) error {
+	current := GetOwner(object)
+	switch {
+	case current == GetOwnerRef(owner):
+		return nil
+	case IsAdopting(owner):
+		return nil
+	case current == "" && (claim || IsManaged(object)):
+		return nil
+	case current == "":
+		return fmt.Errorf("topo object %s is not managed by the operator; set the %s annotation to adopt it", object.ID, AdoptAnnotation)
+	default:
+		return fmt.Errorf("topo object %s is owned by %s; set the %s annotation to adopt it", object.ID, current, AdoptAnnotation)
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package owners
+
+import (
+	"errors"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+const (
+	entityFinalizer = "topo.onosproject.org/entity"
+	kindFinalizer   = "topo"
+)
+
+// TestClaimLegacy verifies that resources added to topo by a previous version of the operator, which did not
+// record ownership, can still update and delete their topo objects after an upgrade
+func TestClaimLegacy(t *testing.T) {
+	// An Entity added by a previous version has the finalizer and only a state
+	entity := &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "micro-onos",
+			Name:       "e2-node-1",
+			UID:        "1234",
+			Finalizers: []string{entityFinalizer},
+		},
+		Status: v1beta1.EntityStatus{
+			ObjectStatus: v1beta1.ObjectStatus{
+				State: v1beta1.StateAdded,
+			},
+		},
+	}
+	object := &topo.Object{ID: "e2:1/5153"}
+	if err := Check(object, entity, CanClaim(&entity.Status.ObjectStatus, object, "e2:1/5153")); err == nil {
+		t.Fatal("expected unclaimed legacy entity to conflict with unmanaged object")
+	}
+	if !ClaimLegacy(entity, entityFinalizer, &entity.Status.ObjectStatus) {
+		t.Fatal("expected legacy entity to claim its object")
+	}
+	if ClaimLegacy(entity, entityFinalizer, &entity.Status.ObjectStatus) {
+		t.Error("expected claimed entity not to be claimed again")
+	}
+
+	// The claim persists through the status conditions set by the reconciler
+	conditions.SetSyncFailed(&entity.Status.ObjectStatus, entity.Generation, v1beta1.ReasonSyncFailed, errors.New("connection refused"))
+	if err := Check(object, entity, CanClaim(&entity.Status.ObjectStatus, object, "e2:1/5153")); err != nil {
+		t.Errorf("expected legacy entity to claim its object: %v", err)
+	}
+
+	// A Kind added by a previous version has the finalizer and an empty status
+	kind := &v1beta1.Kind{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "micro-onos",
+			Name:       "e2-node",
+			Finalizers: []string{kindFinalizer},
+		},
+	}
+	if !ClaimLegacy(kind, kindFinalizer, &kind.Status.ObjectStatus) {
+		t.Fatal("expected legacy kind to claim its object")
+	}
+	kindObject := &topo.Object{ID: "e2-node"}
+	if err := Check(kindObject, kind, CanClaim(&kind.Status.ObjectStatus, kindObject, kind.Name)); err != nil {
+		t.Errorf("expected legacy kind to claim its object: %v", err)
+	}
+
+	// Objects owned by other resources are not claimed
+	owned := &topo.Object{ID: "e2:1/5153"}
+	SetOwner(owned, &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "e2-node-1"}})
+	if err := Check(owned, entity, CanClaim(&entity.Status.ObjectStatus, owned, "e2:1/5153")); err == nil {
+		t.Error("expected legacy entity to conflict with object owned by another resource")
+	}
+}
+
+// TestClaimNew verifies that resources added by this version of the operator do not claim unmanaged objects
+// before they have been synchronized
+func TestClaimNew(t *testing.T) {
+	entity := &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "micro-onos",
+			Name:      "e2-node-1",
+		},
+	}
+	if ClaimLegacy(entity, entityFinalizer, &entity.Status.ObjectStatus) {
+		t.Error("expected entity without finalizer not to claim its object")
+	}
+
+	// The reconciler sets the Adding state before adding the finalizer
+	conditions.SetState(&entity.Status.ObjectStatus, v1beta1.StateAdding, entity.Generation)
+	entity.Finalizers = []string{entityFinalizer}
+	if ClaimLegacy(entity, entityFinalizer, &entity.Status.ObjectStatus) {
+		t.Error("expected new entity not to claim its object")
+	}
+	object := &topo.Object{ID: "e2:1/5153"}
+	if err := Check(object, entity, CanClaim(&entity.Status.ObjectStatus, object, "e2:1/5153")); err == nil {
+		t.Error("expected new entity to conflict with unmanaged object")
+	}
+
+	// An unmanaged object re-created at the URI of a synchronized entity, e.g. by another tool, is not claimed
+	conditions.SetSynced(&entity.Status.ObjectStatus, entity.Generation)
+	if err := Check(object, entity, CanClaim(&entity.Status.ObjectStatus, object, "e2:1/5153")); err == nil {
+		t.Error("expected synchronized entity to conflict with unmanaged object")
+	}
+	entity.Annotations = map[string]string{AdoptAnnotation: "true"}
+	if err := Check(object, entity, CanClaim(&entity.Status.ObjectStatus, object, "e2:1/5153")); err != nil {
+		t.Errorf("expected adopting entity to claim unmanaged object: %v", err)
+	}
+}
+
+// TestClaimMigrate verifies that changing the URI of a resource onto an unmanaged topo object does not take over the
+// object, even if the resource claims its own object
+func TestClaimMigrate(t *testing.T) {
+	entity := &v1beta1.Entity{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "micro-onos",
+			Name:       "e2-node-1",
+			Finalizers: []string{entityFinalizer},
+		},
+		Spec: v1beta1.EntitySpec{
+			URI: "e2:1/5154",
+		},
+		Status: v1beta1.EntityStatus{
+			ObjectStatus: v1beta1.ObjectStatus{
+				State: v1beta1.StateAdded,
+			},
+			AppliedURI: "e2:1/5153",
+		},
+	}
+	ClaimLegacy(entity, entityFinalizer, &entity.Status.ObjectStatus)
+	conditions.SetSynced(&entity.Status.ObjectStatus, entity.Generation)
+
+	applied := &topo.Object{ID: "e2:1/5153"}
+	if err := Check(applied, entity, CanClaim(&entity.Status.ObjectStatus, applied, entity.Status.AppliedURI)); err != nil {
+		t.Errorf("expected legacy entity to claim the object at its applied URI: %v", err)
+	}
+	occupied := &topo.Object{ID: "e2:1/5154"}
+	if err := Check(occupied, entity, CanClaim(&entity.Status.ObjectStatus, occupied, entity.Status.AppliedURI)); err == nil {
+		t.Error("expected entity migrated onto an unmanaged object to conflict")
+	}
+	SetOwner(occupied, &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "micro-onos", Name: "e2-node-2"}})
+	if err := Check(occupied, entity, CanClaim(&entity.Status.ObjectStatus, occupied, entity.Status.AppliedURI)); err == nil {
+		t.Error("expected entity migrated onto an object owned by another resource to conflict")
+	}
+}
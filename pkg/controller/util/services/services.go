@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/k8s"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultServiceName is the name of the topo service used when neither a resource nor its namespace name one
+	DefaultServiceName = "onos-topo"
+
+	// ServiceNameAnnotation is the namespace annotation naming the default topo service for resources in the namespace
+	ServiceNameAnnotation = "topo.onosproject.org/service-name"
+
+	// AllNamespaces allows resources in all namespaces to use a topo Service
+	AllNamespaces = "*"
+
+	// DefaultPortName is the name of the onos-topo API port of the pods selected by a Service
+	DefaultPortName = "grpc"
+
+	caCertKey = "ca.crt"
+)
+
+// ServiceField is the index of Kinds, Entities and Relations by the topo service they use
+const ServiceField = "spec.service"
+
+// GetDefaultServiceName returns the name of the topo service used by resources in the given namespace
+// that do not name a topo service
+func GetDefaultServiceName(ctx context.Context, c client.Client, namespace string) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return DefaultServiceName, nil
+		}
+		return "", err
+	}
+	if serviceName := ns.Annotations[ServiceNameAnnotation]; serviceName != "" {
+		return serviceName, nil
+	}
+	return DefaultServiceName, nil
+}
+
+// GetServiceName returns the name of the topo service for a resource in the given namespace, falling back
+// to the namespace default if serviceName is empty
+func GetServiceName(ctx context.Context, c client.Client, namespace, serviceName string) (string, error) {
+	if serviceName != "" {
+		return serviceName, nil
+	}
+	return GetDefaultServiceName(ctx, c, namespace)
+}
+
+// GetServiceRef returns the topo service for a resource in the given namespace. A serviceRef takes precedence
+// over a serviceName, and a serviceRef without a namespace refers to the resource's own namespace.
+func GetServiceRef(ctx context.Context, c client.Client, namespace string, serviceRef *v1beta1.ServiceReference, serviceName string) (types.NamespacedName, error) {
+	if serviceRef != nil && serviceRef.Name != "" {
+		if serviceRef.Namespace == "" {
+			return types.NamespacedName{Namespace: namespace, Name: serviceRef.Name}, nil
+		}
+		return types.NamespacedName{Namespace: serviceRef.Namespace, Name: serviceRef.Name}, nil
+	}
+	name, err := GetServiceName(ctx, c, namespace, serviceName)
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// GetObjectServiceRef returns the topo service of the given Kind, Entity or Relation resource
+func GetObjectServiceRef(ctx context.Context, c client.Client, object client.Object) (types.NamespacedName, error) {
+	serviceRef, serviceName := getSpecServiceRef(object)
+	return GetServiceRef(ctx, c, object.GetNamespace(), serviceRef, serviceName)
+}
+
+// GetIndexKey returns the key under which the given Kind, Entity or Relation resource is indexed by topo service.
+// Resources that do not name a topo service are indexed under their namespace with an empty service name, since
+// the default topo service of the namespace may change.
+func GetIndexKey(object client.Object) string {
+	serviceRef, serviceName := getSpecServiceRef(object)
+	if serviceRef != nil && serviceRef.Name != "" {
+		if serviceRef.Namespace == "" {
+			return getIndexKey(object.GetNamespace(), serviceRef.Name)
+		}
+		return getIndexKey(serviceRef.Namespace, serviceRef.Name)
+	}
+	return getIndexKey(object.GetNamespace(), serviceName)
+}
+
+// GetIndexKeys returns the keys under which the resources using the given topo service are indexed: the key of
+// the topo service, and the key of the resources not naming a topo service if it is the default of its namespace
+func GetIndexKeys(ctx context.Context, c client.Client, serviceRef types.NamespacedName) ([]string, error) {
+	keys := []string{getIndexKey(serviceRef.Namespace, serviceRef.Name)}
+	defaultName, err := GetDefaultServiceName(ctx, c, serviceRef.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if defaultName == serviceRef.Name {
+		keys = append(keys, getIndexKey(serviceRef.Namespace, ""))
+	}
+	return keys, nil
+}
+
+func getIndexKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// getSpecServiceRef returns the topo service reference and name set in the spec of the given topology resource
+func getSpecServiceRef(object client.Object) (*v1beta1.ServiceReference, string) {
+	switch o := object.(type) {
+	case *v1beta1.Entity:
+		return o.Spec.ServiceRef, o.Spec.ServiceName
+	case *v1beta1.Kind:
+		return o.Spec.ServiceRef, o.Spec.ServiceName
+	case *v1beta1.Relation:
+		return o.Spec.ServiceRef, o.Spec.ServiceName
+	}
+	return nil, ""
+}
+
+// IsAllowed returns whether resources in the given namespace may use the given topo service. Topo services
+// in the resource's own namespace may always be used; topo services in other namespaces must be topo Service
+// resources that list the namespace in their allowedNamespaces. In either case, a topo Service resource may
+// only be used if its target is allowed (see CheckTarget).
+func IsAllowed(ctx context.Context, c client.Client, namespace string, serviceRef types.NamespacedName) (bool, error) {
+	service, err := GetService(ctx, c, serviceRef.Namespace, serviceRef.Name)
+	if err != nil {
+		return false, err
+	} else if service == nil {
+		return serviceRef.Namespace == namespace, nil
+	} else if CheckTarget(service) != nil {
+		return false, nil
+	} else if serviceRef.Namespace == namespace {
+		return true, nil
+	}
+	for _, allowedNamespace := range service.Spec.AllowedNamespaces {
+		if allowedNamespace == namespace || allowedNamespace == AllNamespaces {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CheckTarget returns an error if the given Service may not connect to its onos-topo instance. The pods selected
+// by a Service are always in the Service's own namespace, but an address may point anywhere, so outside the
+// operator's namespace the address must be the cluster DNS name of a Kubernetes Service in the Service's own
+// namespace. Otherwise, a Service could be used to write to an onos-topo instance in another namespace without
+// being allowed by its allowedNamespaces.
+func CheckTarget(service *v1beta1.Service) error {
+	if service.Spec.Selector != nil || service.Spec.Address == "" || service.Namespace == k8s.GetNamespace() {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(service.Spec.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %v", service.Spec.Address, err)
+	}
+	if namespace, ok := getHostNamespace(host); !ok || namespace != service.Namespace {
+		return fmt.Errorf("address %s is not a service in namespace %s", service.Spec.Address, service.Namespace)
+	}
+	return nil
+}
+
+// getHostNamespace returns the namespace of the Kubernetes Service with the given cluster DNS name, i.e.
+// <service>.<namespace>, optionally followed by .svc and the cluster domain
+func getHostNamespace(host string) (string, bool) {
+	host = strings.TrimSuffix(host, ".")
+	host = strings.TrimSuffix(host, "."+k8s.GetClusterDomain())
+	host = strings.TrimSuffix(host, ".svc")
+	names := strings.Split(host, ".")
+	if len(names) != 2 || names[0] == "" || names[1] == "" {
+		return "", false
+	}
+	return names[1], true
+}
+
+// GetService returns the topo Service resource with the given name, or nil if it does not exist
+func GetService(ctx context.Context, c client.Client, namespace, name string) (*v1beta1.Service, error) {
+	service := &v1beta1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return service, nil
+}
+
+// Exists returns whether a topo service with the given name exists in the given namespace, either as a
+// topo Service resource or as a Kubernetes Service
+func Exists(ctx context.Context, c client.Client, namespace, name string) (bool, error) {
+	if service, err := GetService(ctx, c, namespace, name); err != nil {
+		return false, err
+	} else if service != nil {
+		return true, nil
+	}
+	service := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetEndpoints returns the sorted addresses of the ready onos-topo endpoints of the given Service, or an
+// error if the target of the Service is not allowed
+func GetEndpoints(ctx context.Context, c client.Client, service *v1beta1.Service) ([]string, error) {
+	if err := CheckTarget(service); err != nil {
+		return nil, err
+	}
+	if service.Spec.Selector == nil {
+		if service.Spec.Address == "" {
+			return nil, nil
+		}
+		return []string{service.Spec.Address}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(service.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(service.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	portName := service.Spec.Port
+	if portName == "" {
+		portName = DefaultPortName
+	}
+
+	var endpoints []string
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !isPodReady(pod) {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == portName {
+					endpoints = append(endpoints, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port.ContainerPort))))
+				}
+			}
+		}
+	}
+	sort.Strings(endpoints)
+	return endpoints, nil
+}
+
+// GetTLSSecret returns the Secret holding the TLS certificates of the given Service, or nil if the Service
+// does not configure TLS
+func GetTLSSecret(ctx context.Context, c client.Client, service *v1beta1.Service) (*corev1.Secret, error) {
+	if service.Spec.TLS == nil || service.Spec.TLS.SecretName == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: service.Namespace, Name: service.Spec.TLS.SecretName}, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// GetTLSConfig returns the TLS configuration for connecting to the given Service using the certificates in the
+// given TLS Secret
+func GetTLSConfig(service *v1beta1.Service, secret *corev1.Secret) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in secret %s: %v", secret.Name, err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		ServerName:         service.Spec.TLS.ServerName,
+		InsecureSkipVerify: service.Spec.TLS.InsecureSkipVerify,
+	}
+	if ca, ok := secret.Data[caCertKey]; ok {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid CA certificate in secret %s", secret.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
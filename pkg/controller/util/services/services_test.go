@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func newService(namespace, name, address string, allowedNamespaces ...string) *v1beta1.Service {
+	return &v1beta1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: v1beta1.ServiceSpec{
+			Address:           address,
+			AllowedNamespaces: allowedNamespaces,
+		},
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	t.Setenv("CONTROLLER_NAMESPACE", "onos-operator")
+
+	scheme := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newService("micro-onos", "onos-topo", "onos-topo.micro-onos.svc:5150", "tenant-a"),
+			newService("tenant-a", "local", "onos-topo.tenant-a.svc.cluster.local:5150"),
+			newService("tenant-b", "bypass-dns", "onos-topo.micro-onos.svc:5150"),
+			newService("tenant-b", "bypass-short", "onos-topo.micro-onos:5150"),
+			newService("tenant-b", "bypass-ip", "10.96.0.12:5150"),
+			newService("tenant-b", "bypass-pod", "10-244-0-12.micro-onos.pod:5150"),
+			newService("tenant-b", "bypass-operator", "onos-topo:5150"),
+			newService("onos-operator", "external", "topo.example.com:5150", "*"),
+		).
+		Build()
+
+	tests := []struct {
+		description string
+		namespace   string
+		service     types.NamespacedName
+		allowed     bool
+	}{
+		{"service in own namespace", "tenant-a", types.NamespacedName{Namespace: "tenant-a", Name: "local"}, true},
+		{"allowed namespace", "tenant-a", types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}, true},
+		{"not allowed namespace", "tenant-b", types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}, false},
+		{"address of service in other namespace", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "bypass-dns"}, false},
+		{"short address of service in other namespace", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "bypass-short"}, false},
+		{"IP address", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "bypass-ip"}, false},
+		{"pod address in other namespace", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "bypass-pod"}, false},
+		{"address relative to operator namespace", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "bypass-operator"}, false},
+		{"external address in operator namespace", "tenant-b", types.NamespacedName{Namespace: "onos-operator", Name: "external"}, true},
+		{"kubernetes service in own namespace", "tenant-b", types.NamespacedName{Namespace: "tenant-b", Name: "onos-topo"}, true},
+		{"kubernetes service in other namespace", "tenant-b", types.NamespacedName{Namespace: "micro-onos", Name: "other"}, false},
+	}
+	for _, test := range tests {
+		allowed, err := IsAllowed(context.TODO(), c, test.namespace, test.service)
+		if err != nil {
+			t.Fatalf("%s: %v", test.description, err)
+		}
+		if allowed != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t", test.description, test.allowed, allowed)
+		}
+	}
+
+	service, err := GetService(context.TODO(), c, "tenant-b", "bypass-dns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoints, err := GetEndpoints(context.TODO(), c, service); err == nil {
+		t.Errorf("expected endpoints of service bypass-dns to be denied, got %v", endpoints)
+	}
+}
+
+func TestGetIndexKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "slice-1",
+				Annotations: map[string]string{ServiceNameAnnotation: "onos-topo-slice-1"},
+			},
+		}).
+		Build()
+
+	entity := func(namespace string, serviceRef *v1beta1.ServiceReference, serviceName string) *v1beta1.Entity {
+		return &v1beta1.Entity{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "e2-node-1"},
+			Spec:       v1beta1.EntitySpec{ServiceRef: serviceRef, ServiceName: serviceName},
+		}
+	}
+	tests := []struct {
+		description string
+		entity      *v1beta1.Entity
+		service     types.NamespacedName
+		indexed     bool
+	}{
+		{"default service", entity("micro-onos", nil, ""), types.NamespacedName{Namespace: "micro-onos", Name: DefaultServiceName}, true},
+		{"namespace default service", entity("slice-1", nil, ""), types.NamespacedName{Namespace: "slice-1", Name: "onos-topo-slice-1"}, true},
+		{"overridden default service", entity("slice-1", nil, ""), types.NamespacedName{Namespace: "slice-1", Name: DefaultServiceName}, false},
+		{"default service of other namespace", entity("slice-1", nil, ""), types.NamespacedName{Namespace: "micro-onos", Name: DefaultServiceName}, false},
+		{"service name", entity("slice-1", nil, "onos-topo-slice-2"), types.NamespacedName{Namespace: "slice-1", Name: "onos-topo-slice-2"}, true},
+		{"service reference", entity("tenant-a", &v1beta1.ServiceReference{Namespace: "micro-onos", Name: "onos-topo"}, ""), types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}, true},
+		{"service reference without namespace", entity("tenant-a", &v1beta1.ServiceReference{Name: "onos-topo-a"}, ""), types.NamespacedName{Namespace: "tenant-a", Name: "onos-topo-a"}, true},
+	}
+	for _, test := range tests {
+		keys, err := GetIndexKeys(context.TODO(), c, test.service)
+		if err != nil {
+			t.Fatalf("%s: %v", test.description, err)
+		}
+		indexed := false
+		for _, key := range keys {
+			if key == GetIndexKey(test.entity) {
+				indexed = true
+			}
+		}
+		if indexed != test.indexed {
+			t.Errorf("%s: expected indexed %t, got %t", test.description, test.indexed, indexed)
+		}
+		serviceRef, err := GetObjectServiceRef(context.TODO(), c, test.entity)
+		if err != nil {
+			t.Fatalf("%s: %v", test.description, err)
+		}
+		if (serviceRef == test.service) != test.indexed {
+			t.Errorf("%s: index does not match service %s", test.description, serviceRef)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package uris
+
+import (
+	"bytes"
+	"fmt"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"strings"
+	"text/template"
+)
+
+// Data is the data the URI template of a Kind is rendered with for an Entity or Relation of the Kind
+type Data struct {
+	// Name is the name of the resource
+	Name string
+	// Namespace is the namespace of the resource
+	Namespace string
+	// Labels are the labels of the resource
+	Labels map[string]string
+	// Annotations are the annotations of the resource
+	Annotations map[string]string
+	// Kind is the name of the Kind of the resource
+	Kind string
+	// Aspects are the decoded aspects of the resource by aspect type
+	Aspects map[string]interface{}
+	// Source is the URI of the source of a Relation
+	Source string
+	// Target is the URI of the target of a Relation
+	Target string
+}
+
+// NewData returns the data to render a URI template with for a resource with the given metadata, kind and aspects
+func NewData(name, namespace string, labels, annotations map[string]string, kind string, aspects map[string]runtime.RawExtension) (Data, error) {
+	data := Data{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      labels,
+		Annotations: annotations,
+		Kind:        kind,
+		Aspects:     make(map[string]interface{}),
+	}
+	for aspectType, aspect := range aspects {
+		var value interface{}
+		if err := json.Unmarshal(aspect.Raw, &value); err != nil {
+			return data, fmt.Errorf("invalid aspect %s: %s", aspectType, err)
+		}
+		data.Aspects[aspectType] = value
+	}
+	return data, nil
+}
+
+// Parse parses the given URI template
+func Parse(text string) (*template.Template, error) {
+	return template.New("uri").Option("missingkey=error").Parse(text)
+}
+
+// Render renders the given URI template with the given data
+func Render(text string, data Data) (string, error) {
+	t, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	uri := strings.TrimSpace(buf.String())
+	if uri == "" {
+		return "", fmt.Errorf("the URI template rendered an empty URI")
+	}
+	return uri, nil
+}
+
+// GetRelationURI returns the default URI of a Relation of the given kind from the given source to the given target
+func GetRelationURI(kind, source, target string) string {
+	return fmt.Sprintf("%s:%s-%s", kind, source, target)
+}
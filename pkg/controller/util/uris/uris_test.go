@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package uris
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"testing"
+)
+
+// TestRender verifies the rendering of URI templates with the metadata, kind and aspects of a resource
+func TestRender(t *testing.T) {
+	data, err := NewData(
+		"switch-1",
+		"micro-onos",
+		map[string]string{"rack": "r1"},
+		map[string]string{"topo.onosproject.org/protocol": "p4rt"},
+		"switch",
+		map[string]runtime.RawExtension{
+			"onos.topo.P4RTServerInfo": {Raw: []byte(`{"controlEndpoint":{"address":"10.0.0.1","port":9559}}`)},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		err      bool
+	}{
+		{
+			name:     "metadata",
+			template: "{{ .Kind }}:{{ .Namespace }}/{{ .Name }}",
+			uri:      "switch:micro-onos/switch-1",
+		},
+		{
+			name:     "labels and annotations",
+			template: `{{ index .Annotations "topo.onosproject.org/protocol" }}:{{ .Labels.rack }}/{{ .Name }}`,
+			uri:      "p4rt:r1/switch-1",
+		},
+		{
+			name:     "aspects",
+			template: `p4rt:{{ (index .Aspects "onos.topo.P4RTServerInfo").controlEndpoint.address }}:{{ (index .Aspects "onos.topo.P4RTServerInfo").controlEndpoint.port }}`,
+			uri:      "p4rt:10.0.0.1:9559",
+		},
+		{
+			name:     "surrounding space",
+			template: "  {{ .Name }}\n",
+			uri:      "switch-1",
+		},
+		{
+			name:     "missing label",
+			template: "{{ .Labels.row }}/{{ .Name }}",
+			err:      true,
+		},
+		{
+			name:     "missing aspect field",
+			template: `{{ (index .Aspects "onos.topo.P4RTServerInfo").address }}`,
+			err:      true,
+		},
+		{
+			name:     "empty",
+			template: `{{ if .Labels }}{{ end }}`,
+			err:      true,
+		},
+		{
+			name:     "invalid",
+			template: "{{ .Name }",
+			err:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uri, err := Render(test.template, data)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected rendering to fail, got %s", uri)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if uri != test.uri {
+				t.Errorf("expected URI %s, got %s", test.uri, uri)
+			}
+		})
+	}
+}
+
+// TestNewData verifies that aspects that are not valid JSON are rejected
+func TestNewData(t *testing.T) {
+	aspects := map[string]runtime.RawExtension{
+		"onos.topo.Location": {Raw: []byte(`{"lat":`)},
+	}
+	if _, err := NewData("switch-1", "micro-onos", nil, nil, "switch", aspects); err == nil {
+		t.Error("expected invalid aspect to be rejected")
+	}
+}
+
+// TestGetRelationURI verifies the default URI of a Relation
+func TestGetRelationURI(t *testing.T) {
+	tests := []struct {
+		kind   string
+		source string
+		target string
+		uri    string
+	}{
+		{kind: "controls", source: "onos-config", target: "p4rt:switch-1", uri: "controls:onos-config-p4rt:switch-1"},
+		{kind: "contains", source: "p4rt:switch-1", target: "p4rt:switch-1/1/0", uri: "contains:p4rt:switch-1-p4rt:switch-1/1/0"},
+	}
+	for _, test := range tests {
+		if uri := GetRelationURI(test.kind, test.source, test.target); uri != test.uri {
+			t.Errorf("expected URI %s, got %s", test.uri, uri)
+		}
+	}
+}
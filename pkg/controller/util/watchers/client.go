@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watchers
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Client returns a client of the given topo service that records the revisions of the objects updated through it,
+// so that the watch events of the operator's own updates are not published
+func (w *Watcher) Client(service types.NamespacedName, client topo.TopoClient) topo.TopoClient {
+	return &topoClient{
+		TopoClient: client,
+		watcher:    w,
+		service:    service,
+	}
+}
+
+// topoClient is a topo client recording the revisions of updated objects in the watcher
+type topoClient struct {
+	topo.TopoClient
+	watcher *Watcher
+	service types.NamespacedName
+}
+
+func (c *topoClient) Update(ctx context.Context, request *topo.UpdateRequest, opts ...grpc.CallOption) (*topo.UpdateResponse, error) {
+	response, err := c.TopoClient.Update(ctx, request, opts...)
+	if err == nil && response.Object != nil {
+		c.watcher.setApplied(c.service, response.Object)
+	}
+	return response, err
+}
@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watchers
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/grpc"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("controller", "util", "watchers")
+
+const (
+	eventBufferSize    = 1000
+	watchRetryInterval = 10 * time.Second
+	// publishRetryInterval is the interval at which changes dropped while the event buffer was full are republished
+	publishRetryInterval = 10 * time.Second
+)
+
+// NewWatcher creates a new topo watcher
+func NewWatcher(conns *grpc.ConnManager) *Watcher {
+	return &Watcher{
+		conns:     conns,
+		entities:  make(chan event.GenericEvent, eventBufferSize),
+		kinds:     make(chan event.GenericEvent, eventBufferSize),
+		relations: make(chan event.GenericEvent, eventBufferSize),
+		watches:   make(map[types.NamespacedName]context.CancelFunc),
+		changed:   make(map[string]bool),
+		applied:   make(map[appliedKey]topo.Revision),
+	}
+}
+
+// Watcher subscribes to changes to the objects of topo services and publishes the resources owning the
+// updated or deleted objects as generic events, allowing the topology controllers to repair changes made
+// directly in onos-topo. Updates made by the operator itself are not published, and events for resources that
+// are already awaiting reconciliation are coalesced. The watches run while the watcher is started by the manager.
+type Watcher struct {
+	conns     *grpc.ConnManager
+	entities  chan event.GenericEvent
+	kinds     chan event.GenericEvent
+	relations chan event.GenericEvent
+	// ctx is the context of the manager, set when the watcher is started
+	ctx     context.Context
+	watches map[types.NamespacedName]context.CancelFunc
+	// changed is the set of resources whose topo objects have changed since they were last verified, and whether
+	// an event has been queued for each of them
+	changed map[string]bool
+	// applied is the last revision of each topo object updated by the operator
+	applied map[appliedKey]topo.Revision
+	mu      sync.Mutex
+}
+
+// appliedKey identifies a topo object of a topo service
+type appliedKey struct {
+	service types.NamespacedName
+	id      topo.ID
+}
+
+// Entities returns the channel of events for Entity resources
+func (w *Watcher) Entities() <-chan event.GenericEvent {
+	return w.entities
+}
+
+// Kinds returns the channel of events for Kind resources
+func (w *Watcher) Kinds() <-chan event.GenericEvent {
+	return w.kinds
+}
+
+// Relations returns the channel of events for Relation resources
+func (w *Watcher) Relations() <-chan event.GenericEvent {
+	return w.relations
+}
+
+var _ manager.Runnable = &Watcher{}
+
+// Start starts the watches of topo services and republishes the changes dropped while the event buffer was full
+// until the manager is stopped, which stops the watches
+func (w *Watcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.ctx = ctx
+	for name := range w.watches {
+		w.start(name)
+	}
+	w.mu.Unlock()
+
+	ticker := time.NewTicker(publishRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.republish()
+		}
+	}
+}
+
+// Watch starts watching the topo service with the given name if it is not already being watched. The watch
+// is deferred until the watcher is started.
+func (w *Watcher) Watch(name types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.watches[name]; ok {
+		return
+	}
+	w.watches[name] = nil
+	if w.ctx != nil {
+		w.start(name)
+	}
+}
+
+// start starts the watch of the given topo service. The caller must hold the lock.
+func (w *Watcher) start(name types.NamespacedName) {
+	log.Infof("Starting watch of topo service %s", name)
+	ctx, cancel := context.WithCancel(w.ctx)
+	w.watches[name] = cancel
+	go w.watch(ctx, name)
+}
+
+// Unwatch stops watching the topo service with the given name
+func (w *Watcher) Unwatch(name types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cancel, ok := w.watches[name]; ok {
+		log.Infof("Stopping watch of topo service %s", name)
+		if cancel != nil {
+			cancel()
+		}
+		delete(w.watches, name)
+	}
+}
+
+// ClearChanged returns whether the topo object of the given resource has changed since the last call,
+// clearing the change
+func (w *Watcher) ClearChanged(owner client.Object) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ref := owners.GetOwnerRef(owner)
+	_, changed := w.changed[ref]
+	delete(w.changed, ref)
+	return changed
+}
+
+// watch publishes the changes to the objects of the given topo service until the context is canceled,
+// re-subscribing when the watch fails
+func (w *Watcher) watch(ctx context.Context, name types.NamespacedName) {
+	for {
+		if err := w.watchObjects(ctx, name); err != nil {
+			log.Warnf("Failed to watch topo service %s, %s", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+func (w *Watcher) watchObjects(ctx context.Context, name types.NamespacedName) error {
+	conn, err := w.conns.Connect(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	stream, err := topo.CreateTopoClient(conn.ClientConn).Watch(ctx, &topo.WatchRequest{Noreplay: true})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		switch resp.Event.Type {
+		case topo.EventType_UPDATED:
+			if !w.clearApplied(name, &resp.Event.Object) {
+				w.publish(&resp.Event.Object)
+			}
+		case topo.EventType_REMOVED:
+			// Removals do not change the revision of the object, so they are always published
+			w.mu.Lock()
+			delete(w.applied, appliedKey{service: name, id: resp.Event.Object.ID})
+			w.mu.Unlock()
+			w.publish(&resp.Event.Object)
+		}
+	}
+}
+
+// setApplied records the revision of a topo object of the given topo service updated by the operator
+func (w *Watcher) setApplied(service types.NamespacedName, object *topo.Object) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.applied[appliedKey{service: service, id: object.ID}] = object.Revision
+}
+
+// clearApplied returns whether the given revision of a topo object of the given topo service was updated by the
+// operator, clearing the revision
+func (w *Watcher) clearApplied(service types.NamespacedName, object *topo.Object) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := appliedKey{service: service, id: object.ID}
+	if revision, ok := w.applied[key]; ok && revision == object.Revision {
+		delete(w.applied, key)
+		return true
+	}
+	return false
+}
+
+// publish enqueues the resource owning the given topo object unless an event for it is already queued. If the
+// event buffer is full, the change is recorded and republished later.
+func (w *Watcher) publish(object *topo.Object) {
+	ref := owners.GetOwner(object)
+	if _, _, ok := newEvent(ref); !ok {
+		return
+	}
+
+	log.Debugf("Topo object %s of %s changed", object.ID, ref)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if queued := w.changed[ref]; queued {
+		return
+	}
+	queued := w.send(ref)
+	if !queued {
+		log.Warnf("Event buffer is full; deferring change of topo object %s of %s", object.ID, ref)
+	}
+	w.changed[ref] = queued
+}
+
+// republish enqueues the changed resources whose events were dropped while the event buffer was full
+func (w *Watcher) republish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ref, queued := range w.changed {
+		if !queued {
+			w.changed[ref] = w.send(ref)
+		}
+	}
+}
+
+// send enqueues the resource with the given owner reference, returning false if the event buffer is full.
+// The caller must hold the lock.
+func (w *Watcher) send(ref string) bool {
+	kind, owner, _ := newEvent(ref)
+	var events chan event.GenericEvent
+	switch kind {
+	case "Entity":
+		events = w.entities
+	case "Kind":
+		events = w.kinds
+	case "Relation":
+		events = w.relations
+	}
+	select {
+	case events <- event.GenericEvent{Object: owner}:
+		return true
+	default:
+		return false
+	}
+}
+
+// newEvent returns the kind and an object of the resource with the given owner reference
+func newEvent(ref string) (string, client.Object, bool) {
+	kind, namespace, name, ok := owners.ParseOwnerRef(ref)
+	if !ok {
+		return "", nil, false
+	}
+	objectMeta := metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      name,
+	}
+	switch kind {
+	case "Entity":
+		return kind, &v1beta1.Entity{ObjectMeta: objectMeta}, true
+	case "Kind":
+		return kind, &v1beta1.Kind{ObjectMeta: objectMeta}, true
+	case "Relation":
+		return kind, &v1beta1.Relation{ObjectMeta: objectMeta}, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2020-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package watchers
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-operator/pkg/apis/topo/v1beta1"
+	"github.com/onosproject/onos-operator/pkg/controller/util/owners"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func newObject(id topo.ID, revision topo.Revision, owner *v1beta1.Entity) *topo.Object {
+	object := &topo.Object{ID: id, Revision: revision}
+	owners.SetOwner(object, owner)
+	return object
+}
+
+func TestPublish(t *testing.T) {
+	watcher := NewWatcher(nil)
+	entity1 := &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "micro-onos", Name: "e2-node-1"}}
+	entity2 := &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "micro-onos", Name: "e2-node-2"}}
+
+	// Changes of a resource awaiting reconciliation are coalesced
+	watcher.publish(newObject("e2:1", 1, entity1))
+	watcher.publish(newObject("e2:1", 2, entity1))
+	if n := len(watcher.Entities()); n != 1 {
+		t.Errorf("expected 1 event, got %d", n)
+	}
+	<-watcher.Entities()
+	if !watcher.ClearChanged(entity1) {
+		t.Error("expected entity to be changed")
+	}
+	watcher.publish(newObject("e2:1", 3, entity1))
+	if n := len(watcher.Entities()); n != 1 {
+		t.Errorf("expected 1 event after reconciliation, got %d", n)
+	}
+	<-watcher.Entities()
+	watcher.ClearChanged(entity1)
+
+	// Publishing does not block when the buffer is full, and dropped changes are not coalesced
+	for i := 0; i < eventBufferSize; i++ {
+		watcher.publish(newObject("e2:1", topo.Revision(i), &v1beta1.Entity{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: fmt.Sprintf("e2-node-%d", i)},
+		}))
+	}
+	watcher.publish(newObject("e2:2", 1, entity2))
+	<-watcher.Entities()
+	watcher.publish(newObject("e2:2", 2, entity2))
+	if !isQueued(watcher, entity2) {
+		t.Error("expected change of entity after dropped change to be queued")
+	}
+
+	// Dropped changes are republished once the buffer has room
+	entity3 := &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "micro-onos", Name: "e2-node-3"}}
+	watcher.publish(newObject("e2:3", 1, entity3))
+	watcher.republish()
+	if isQueued(watcher, entity3) {
+		t.Error("expected change of entity to remain dropped while the buffer is full")
+	}
+	<-watcher.Entities()
+	watcher.republish()
+	if !isQueued(watcher, entity3) {
+		t.Error("expected dropped change to be republished")
+	}
+	if !watcher.ClearChanged(entity3) {
+		t.Error("expected entity to be changed")
+	}
+}
+
+// isQueued returns whether an event has been queued for the given resource
+func isQueued(watcher *Watcher, owner *v1beta1.Entity) bool {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	return watcher.changed[owners.GetOwnerRef(owner)]
+}
+
+func TestApplied(t *testing.T) {
+	watcher := NewWatcher(nil)
+	service := types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}
+	other := types.NamespacedName{Namespace: "tenant-a", Name: "onos-topo"}
+	entity := &v1beta1.Entity{ObjectMeta: metav1.ObjectMeta{Namespace: "micro-onos", Name: "e2-node-1"}}
+
+	watcher.setApplied(service, newObject("e2:1", 2, entity))
+	if watcher.clearApplied(service, newObject("e2:1", 1, entity)) {
+		t.Error("expected previous revision not to be applied")
+	}
+	if watcher.clearApplied(other, newObject("e2:1", 2, entity)) {
+		t.Error("expected object of other topo service not to be applied")
+	}
+	if !watcher.clearApplied(service, newObject("e2:1", 2, entity)) {
+		t.Error("expected applied revision to be ignored")
+	}
+	if watcher.clearApplied(service, newObject("e2:1", 2, entity)) {
+		t.Error("expected applied revision to be cleared")
+	}
+}
+
+func TestStart(t *testing.T) {
+	watcher := NewWatcher(nil)
+	service := types.NamespacedName{Namespace: "micro-onos", Name: "onos-topo"}
+
+	// Watches requested before the watcher is started are deferred
+	watcher.Watch(service)
+	if cancel := watcher.watches[service]; cancel != nil {
+		t.Error("expected watch not to be started before the watcher")
+	}
+	watcher.Unwatch(service)
+	if _, ok := watcher.watches[service]; ok {
+		t.Error("expected deferred watch to be removed")
+	}
+}